@@ -0,0 +1,242 @@
+package copier
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize — размер буфера копирования, как у io.Copy.
+const DefaultBufferSize = 32 * 1024
+
+// Options настраивает Copy. Нулевое значение или nil — копирование
+// буфером DefaultBufferSize без ограничения скорости и без отчёта.
+type Options struct {
+	// BufferSize — размер буфера. Буферы переиспользуются между вызовами.
+	BufferSize int
+	// Rate ограничивает скорость в байтах в секунду, 0 — без ограничения.
+	Rate int64
+	// Progress вызывается после каждой записанной части
+	// с общим числом скопированных байт.
+	Progress func(written int64)
+}
+
+func (o *Options) bufferSize() int {
+	if o == nil || o.BufferSize <= 0 {
+		return DefaultBufferSize
+	}
+	return o.BufferSize
+}
+
+func (o *Options) rate() int64 {
+	if o == nil || o.Rate <= 0 {
+		return 0
+	}
+	return o.Rate
+}
+
+func (o *Options) progress() func(int64) {
+	if o == nil {
+		return nil
+	}
+	return o.Progress
+}
+
+// Буферы от 512 байт до 1 МиБ переиспользуются пулами по степеням двойки,
+// буферы больше выделяются на каждый вызов.
+const (
+	minPoolShift = 9
+	maxPoolShift = 20
+)
+
+var pools [maxPoolShift - minPoolShift + 1]sync.Pool
+
+func poolIndex(size int) int {
+	if size > 1<<maxPoolShift {
+		return -1
+	}
+	return max(bits.Len(uint(size-1)), minPoolShift) - minPoolShift
+}
+
+// getBuffer возвращает буфер ёмкостью не меньше size; его длина равна ёмкости.
+func getBuffer(size int) *[]byte {
+	i := poolIndex(size)
+	if i < 0 {
+		buf := make([]byte, size)
+		return &buf
+	}
+	if buf, ok := pools[i].Get().(*[]byte); ok {
+		return buf
+	}
+	buf := make([]byte, 1<<(i+minPoolShift))
+	return &buf
+}
+
+func putBuffer(buf *[]byte) {
+	i := poolIndex(cap(*buf))
+	if i < 0 || cap(*buf) != 1<<(i+minPoolShift) {
+		return
+	}
+	*buf = (*buf)[:cap(*buf)]
+	pools[i].Put(buf)
+}
+
+type copier struct {
+	ctx      context.Context
+	rate     int64
+	progress func(int64)
+	start    time.Time
+	written  int64
+}
+
+func (c *copier) chunk(size int) int {
+	if c.rate > 0 && int64(size) > c.rate {
+		return int(c.rate)
+	}
+	return size
+}
+
+// done ждёт, если копирование идёт быстрее Rate.
+func (c *copier) done(n int) error {
+	c.written += int64(n)
+	if c.progress != nil && n > 0 {
+		c.progress(c.written)
+	}
+	if c.rate == 0 {
+		return nil
+	}
+
+	due := c.start.Add(transferTime(c.written, c.rate))
+	wait := time.Until(due)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// transferTime возвращает, сколько длится передача written байт со скоростью rate байт в секунду.
+// Целые секунды и остаток считаются отдельно, чтобы written*time.Second не переполнялся.
+func transferTime(written, rate int64) time.Duration {
+	seconds, rest := written/rate, written%rate
+	if seconds >= math.MaxInt64/int64(time.Second) {
+		return math.MaxInt64
+	}
+
+	hi, lo := bits.Mul64(uint64(rest), uint64(time.Second))
+	nanoseconds, _ := bits.Div64(hi, lo, uint64(rate))
+	return time.Duration(seconds)*time.Second + time.Duration(nanoseconds)
+}
+
+func (c *copier) write(w io.Writer, p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if err := c.ctx.Err(); err != nil {
+			return written, err
+		}
+
+		part := p[:c.chunk(len(p))]
+		n, err := w.Write(part)
+		written += n
+		if errDone := c.done(n); errDone != nil {
+			return written, errDone
+		}
+		if err != nil {
+			return written, err
+		}
+		if n != len(part) {
+			return written, io.ErrShortWrite
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+type writer struct {
+	c *copier
+	w io.Writer
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	return w.c.write(w.w, p)
+}
+
+// reader считает прогресс по прочитанным байтам: приёмник пишет их сам.
+type reader struct {
+	c *copier
+	r io.Reader
+}
+
+func (r reader) Read(p []byte) (int, error) {
+	if err := r.c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.r.Read(p[:r.c.chunk(len(p))])
+	if errDone := r.c.done(n); errDone != nil {
+		return n, errDone
+	}
+	return n, err
+}
+
+// Copy копирует r в w до io.EOF и возвращает число записанных байт.
+// Если r реализует io.WriterTo или w — io.ReaderFrom, копирование идёт через них,
+// без промежуточного буфера; без Rate, Progress и отменяемого ctx они получают
+// исходные w и r, так что, например, *os.File сохраняет sendfile.
+// Отмена ctx проверяется между частями: уже начатые Read и Write дожидаются завершения.
+func Copy(ctx context.Context, r io.Reader, w io.Writer, options *Options) (int64, error) {
+	c := &copier{ctx: ctx, rate: options.rate(), progress: options.progress(), start: time.Now()}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if c.rate == 0 && c.progress == nil && ctx.Done() == nil {
+		if wt, ok := r.(io.WriterTo); ok {
+			return wt.WriteTo(w)
+		}
+		if rf, ok := w.(io.ReaderFrom); ok {
+			return rf.ReadFrom(r)
+		}
+	}
+
+	if wt, ok := r.(io.WriterTo); ok {
+		_, err := wt.WriteTo(writer{c: c, w: w})
+		return c.written, err
+	}
+	if rf, ok := w.(io.ReaderFrom); ok {
+		n, err := rf.ReadFrom(reader{c: c, r: r})
+		return n, err
+	}
+
+	bufp := getBuffer(options.bufferSize())
+	defer putBuffer(bufp)
+	buf := (*bufp)[:options.bufferSize()]
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return c.written, err
+		}
+
+		n, err := r.Read(buf[:c.chunk(len(buf))])
+		if n > 0 {
+			if _, errWrite := c.write(w, buf[:n]); errWrite != nil {
+				return c.written, errWrite
+			}
+		}
+		if err == io.EOF {
+			return c.written, nil
+		}
+		if err != nil {
+			return c.written, err
+		}
+	}
+}
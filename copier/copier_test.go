@@ -0,0 +1,298 @@
+package copier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+// plainWriter скрывает io.ReaderFrom у bytes.Buffer.
+type plainWriter struct {
+	b *bytes.Buffer
+}
+
+func (w plainWriter) Write(p []byte) (int, error) {
+	return w.b.Write(p)
+}
+
+var text = strings.Repeat("abcdefghijklmnopqrstuvwxyz", 100)
+
+func TestCopy(t *testing.T) {
+	var tests = []struct {
+		name        string
+		reader      func() io.Reader
+		writer      func(b *bytes.Buffer) io.Writer
+		options     *Options
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Case buffer loop",
+			reader:   func() io.Reader { return iotest.HalfReader(strings.NewReader(text)) },
+			writer:   func(b *bytes.Buffer) io.Writer { return plainWriter{b} },
+			options:  &Options{BufferSize: 7},
+			expected: text,
+		},
+		{
+			name:     "Case writer to",
+			reader:   func() io.Reader { return strings.NewReader(text) },
+			writer:   func(b *bytes.Buffer) io.Writer { return plainWriter{b} },
+			expected: text,
+		},
+		{
+			name:     "Case reader from",
+			reader:   func() io.Reader { return iotest.OneByteReader(strings.NewReader(text)) },
+			writer:   func(b *bytes.Buffer) io.Writer { return b },
+			expected: text,
+		},
+		{
+			name:     "Case empty reader",
+			reader:   func() io.Reader { return iotest.OneByteReader(strings.NewReader("")) },
+			writer:   func(b *bytes.Buffer) io.Writer { return plainWriter{b} },
+			expected: "",
+		},
+		{
+			name: "Case read error after data",
+			reader: func() io.Reader {
+				return io.MultiReader(iotest.OneByteReader(strings.NewReader("abc")), iotest.ErrReader(testutils.FakeReadError))
+			},
+			writer:      func(b *bytes.Buffer) io.Writer { return plainWriter{b} },
+			expected:    "abc",
+			expectedErr: testutils.FakeReadError,
+		},
+		{
+			name:   "Case write error",
+			reader: func() io.Reader { return iotest.OneByteReader(strings.NewReader(text)) },
+			writer: func(b *bytes.Buffer) io.Writer {
+				return testutils.WriterFunc(func(p []byte) (n int, err error) { return 0, testutils.FakeWriteError })
+			},
+			expectedErr: testutils.FakeWriteError,
+		},
+		{
+			name:   "Case short write",
+			reader: func() io.Reader { return iotest.OneByteReader(strings.NewReader(text)) },
+			writer: func(b *bytes.Buffer) io.Writer {
+				return testutils.WriterFunc(func(p []byte) (n int, err error) { return 0, nil })
+			},
+			expectedErr: io.ErrShortWrite,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := bytes.NewBuffer(nil)
+			n, err := Copy(context.Background(), test.reader(), test.writer(b), test.options)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if got := b.String(); got != test.expected {
+				t.Errorf("unexpected value: got %q, expected %q\n", got, test.expected)
+			}
+			if n != int64(len(test.expected)) {
+				t.Errorf("unexpected value: got %v, expected %v\n", n, len(test.expected))
+			}
+		})
+	}
+}
+
+func TestCopyProgress(t *testing.T) {
+	var tests = []struct {
+		name   string
+		reader io.Reader
+		writer func(b *bytes.Buffer) io.Writer
+	}{
+		{name: "Case buffer loop", reader: iotest.HalfReader(strings.NewReader(text)), writer: func(b *bytes.Buffer) io.Writer { return plainWriter{b} }},
+		{name: "Case writer to", reader: strings.NewReader(text), writer: func(b *bytes.Buffer) io.Writer { return plainWriter{b} }},
+		{name: "Case reader from", reader: iotest.HalfReader(strings.NewReader(text)), writer: func(b *bytes.Buffer) io.Writer { return b }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			reports := make([]int64, 0)
+			options := &Options{BufferSize: 100, Progress: func(written int64) { reports = append(reports, written) }}
+
+			_, err := Copy(context.Background(), test.reader, test.writer(bytes.NewBuffer(nil)), options)
+			if err != nil {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, nil)
+			}
+			if len(reports) == 0 || reports[len(reports)-1] != int64(len(text)) {
+				t.Errorf("unexpected value: got %v, expected last %v\n", reports, len(text))
+			}
+			for i := 1; i < len(reports); i++ {
+				if reports[i] <= reports[i-1] {
+					t.Errorf("unexpected value: got %v, expected increasing reports\n", reports)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestCopyRate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	n, err := Copy(context.Background(), strings.NewReader(strings.Repeat("a", 300)), plainWriter{bytes.NewBuffer(nil)}, &Options{Rate: 1000})
+	elapsed := time.Since(start)
+
+	if err != nil || n != 300 {
+		t.Errorf("unexpected value: got %v %v, expected %v %v\n", n, err, 300, nil)
+	}
+	if elapsed < 250*time.Millisecond || elapsed > time.Second {
+		t.Errorf("unexpected duration: got %v, expected about %v\n", elapsed, 300*time.Millisecond)
+	}
+}
+
+func TestTransferTime(t *testing.T) {
+	var tests = []struct {
+		name     string
+		written  int64
+		rate     int64
+		expected time.Duration
+	}{
+		{
+			name:     "Case small",
+			written:  300,
+			rate:     1000,
+			expected: 300 * time.Millisecond,
+		},
+		{
+			name:     "Case over 9.2GB",
+			written:  10 << 30,
+			rate:     1 << 30,
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "Case remainder of a large value",
+			written:  math.MaxInt64,
+			rate:     1 << 40,
+			expected: time.Duration(math.MaxInt64>>40)*time.Second + time.Duration((math.MaxInt64&(1<<40-1))*1e9>>40),
+		},
+		{
+			name:     "Case too long",
+			written:  math.MaxInt64,
+			rate:     1,
+			expected: math.MaxInt64,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := transferTime(test.written, test.rate); got != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestCopyContext(t *testing.T) {
+	endless := func() io.Reader {
+		return testutils.ReaderFunc(func(p []byte) (n int, err error) {
+			return copy(p, "a"), nil
+		})
+	}
+
+	var tests = []struct {
+		name    string
+		writer  func(b *bytes.Buffer) io.Writer
+		options *Options
+	}{
+		{name: "Case buffer loop", writer: func(b *bytes.Buffer) io.Writer { return plainWriter{b} }},
+		{name: "Case reader from", writer: func(b *bytes.Buffer) io.Writer { return b }},
+		{name: "Case waiting for rate", writer: func(b *bytes.Buffer) io.Writer { return plainWriter{b} }, options: &Options{Rate: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := Copy(ctx, endless(), test.writer(bytes.NewBuffer(nil)), test.options)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, context.DeadlineExceeded)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("unexpected duration: got %v, expected about %v\n", elapsed, 10*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestBufferPool(t *testing.T) {
+	var tests = []struct {
+		name        string
+		size        int
+		expectedCap int
+	}{
+		{name: "Case small", size: 64, expectedCap: 512},
+		{name: "Case size class", size: 4096, expectedCap: 4096},
+		{name: "Case between classes", size: 5000, expectedCap: 8192},
+		{name: "Case too large for pool", size: 1<<20 + 1, expectedCap: 1<<20 + 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := getBuffer(test.size)
+			if cap(*buf) != test.expectedCap || len(*buf) < test.size {
+				t.Errorf("unexpected value: got %v/%v, expected %v\n", len(*buf), cap(*buf), test.expectedCap)
+			}
+			putBuffer(buf)
+		})
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		putBuffer(getBuffer(64))
+	})
+	if allocs >= 2 {
+		t.Errorf("unexpected value: got %v allocations, expected pooled buffers\n", allocs)
+	}
+}
+
+// readerFromFile записывает, получил ли ReadFrom исходный reader.
+type readerFromFile struct {
+	bytes.Buffer
+	src io.Reader
+}
+
+func (w *readerFromFile) ReadFrom(r io.Reader) (int64, error) {
+	w.src = r
+	return w.Buffer.ReadFrom(r)
+}
+
+func TestCopyUnwrapped(t *testing.T) {
+	src := strings.NewReader(text)
+
+	w := &readerFromFile{}
+	if _, err := Copy(context.Background(), iotest.HalfReader(src), w, nil); err != nil {
+		t.Fatalf("unexpected error: got %v, expected %v\n", err, nil)
+	}
+	if _, ok := w.src.(reader); ok {
+		t.Errorf("unexpected value: got wrapped reader, expected the source\n")
+	}
+
+	w = &readerFromFile{}
+	if _, err := Copy(context.Background(), iotest.HalfReader(strings.NewReader(text)), w, &Options{Progress: func(int64) {}}); err != nil {
+		t.Fatalf("unexpected error: got %v, expected %v\n", err, nil)
+	}
+	if _, ok := w.src.(reader); !ok {
+		t.Errorf("unexpected value: got %T, expected wrapped reader for progress\n", w.src)
+	}
+	if w.String() != text {
+		t.Errorf("unexpected value: got %d bytes, expected %d\n", w.Len(), len(text))
+	}
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/ticket"
+)
+
+// Коды возврата команды tickets.
+const (
+	ExitOK = iota
+	ExitFailure
+	ExitUsage
+	ExitTimeout
+	ExitRead
+	ExitParse
+	ExitConflict
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type options struct {
+	user      string
+	status    string
+	expr      string
+	since     string
+	until     string
+	timeout   time.Duration
+	output    string
+	format    string
+	strict    bool
+	maxErrors int
+	maxLine   int
+	longLines string
+	split     string
+	statuses  string
+	location  string
+	layouts   []string
+	follow    bool
+	interval  time.Duration
+	index     string
+	dedup     string
+	files     []string
+}
+
+func parseOptions(args []string, stderr io.Writer) (*options, error) {
+	opts := &options{}
+
+	flags := flag.NewFlagSet("tickets", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: tickets [flags] [file ...]")
+		fmt.Fprintln(flags.Output(), "       tickets -follow [flags] file")
+		fmt.Fprintln(flags.Output(), "       tickets serve [-addr :8080] [-log file] [-timeout 10s] [-max-body bytes]")
+		fmt.Fprintln(flags.Output(), "reads standard input when no files are given or the file is \"-\"")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&opts.user, "user", "", "only tickets of the user")
+	flags.StringVar(&opts.status, "status", "", "only tickets with the status")
+	flags.StringVar(&opts.expr, "filter", "", "filter expression, e.g. 'user in (alice,bob) and date >= 2024-03-01'")
+	flags.StringVar(&opts.since, "since", "", "only tickets dated on or after the day (2006-01-02)")
+	flags.StringVar(&opts.until, "until", "", "only tickets dated on or before the day (2006-01-02)")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "stop reading after the duration, 0 means no limit")
+	flags.StringVar(&opts.output, "output", "json", "output format: "+strings.Join(ticket.OutputFormats, ", "))
+	flags.StringVar(&opts.format, "format", "underscore", "input format: "+strings.Join(ticket.DecoderNames(), ", "))
+	flags.BoolVar(&opts.strict, "strict", false, "fail on the first line that is not a ticket")
+	flags.IntVar(&opts.maxErrors, "max-errors", -1, "fail when more than N lines are not tickets, -1 means no limit")
+	flags.IntVar(&opts.maxLine, "max-line", ticket.DefaultMaxLineSize, "maximum line size in bytes")
+	flags.StringVar(&opts.longLines, "long-lines", "error", "what to do with longer lines: error, truncate, skip")
+	flags.StringVar(&opts.split, "split", "lines", "record delimiter: "+strings.Join(ticket.SplitNames(), ", "))
+	flags.StringVar(&opts.location, "location", "UTC", "time zone of dates without an offset, e.g. Europe/Moscow or Local")
+	flags.Func("date-layout", "date layout to try, in Go notation; repeat to try several in order (default: "+strings.Join(ticket.DefaultDateLayouts, ", ")+")", func(layout string) error {
+		opts.layouts = append(opts.layouts, layout)
+		return nil
+	})
+	flags.BoolVar(&opts.follow, "follow", false, "print the tickets of the file and keep reading it as it grows, like tail -F")
+	flags.DurationVar(&opts.interval, "interval", ticket.DefaultFollowInterval, "how often -follow checks the file for new lines")
+	flags.StringVar(&opts.index, "index", "", "index file for the log; built or updated before the query, needs exactly one file and default reading options")
+	flags.StringVar(&opts.dedup, "dedup", "", "drop repeated tickets by key: id, id+date, line; conflicting repeats are reported")
+	flags.StringVar(&opts.statuses, "statuses", "", "file with extra status aliases, one 'status = alias, alias' per line")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	opts.files = flags.Args()
+	if len(opts.files) == 0 {
+		opts.files = []string{"-"}
+	}
+
+	return opts, nil
+}
+
+func (opts *options) timeLocation() (*time.Location, error) {
+	if opts.location == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(opts.location)
+}
+
+func (opts *options) dates() (ticket.DateParser, error) {
+	location, err := opts.timeLocation()
+	if err != nil {
+		return ticket.DateParser{}, err
+	}
+	return ticket.DateParser{Layouts: opts.layouts, Location: location}, nil
+}
+
+// query переносит -user, -status, -since и -until в запрос к индексу. Прочие условия
+// проверяются фильтром уже на прочитанных тикетах.
+func (opts *options) query() (ticket.IndexQuery, error) {
+	location, err := opts.timeLocation()
+	if err != nil {
+		return ticket.IndexQuery{}, err
+	}
+
+	query := ticket.IndexQuery{User: opts.user, Status: opts.status}
+	if opts.since != "" {
+		if query.Since, err = time.ParseInLocation(ticket.DateLayout, opts.since, location); err != nil {
+			return ticket.IndexQuery{}, err
+		}
+	}
+	if opts.until != "" {
+		until, err := time.ParseInLocation(ticket.DateLayout, opts.until, location)
+		if err != nil {
+			return ticket.IndexQuery{}, err
+		}
+		query.Until = until.AddDate(0, 0, 1)
+	}
+
+	return query, nil
+}
+
+func (opts *options) filter() (ticket.Filter, error) {
+	conditions := make([]string, 0)
+	if opts.user != "" {
+		conditions = append(conditions, "user = "+strconv.Quote(opts.user))
+	}
+	if opts.status != "" {
+		conditions = append(conditions, "status = "+strconv.Quote(opts.status))
+	}
+	if opts.since != "" {
+		conditions = append(conditions, "date >= "+strconv.Quote(opts.since))
+	}
+	if opts.until != "" {
+		conditions = append(conditions, "date <= "+strconv.Quote(opts.until))
+	}
+
+	location, err := opts.timeLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := ticket.CompileFilterIn(strings.Join(conditions, " and "), location)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := ticket.CompileFilterIn(opts.expr, location)
+	if err != nil {
+		return nil, fmt.Errorf("--filter: %w", err)
+	}
+
+	return filter.And(expr), nil
+}
+
+func (opts *options) diagnostics() *ticket.Diagnostics {
+	switch {
+	case opts.strict:
+		return &ticket.Diagnostics{Policy: ticket.FailFast}
+	case opts.maxErrors >= 0:
+		return &ticket.Diagnostics{Policy: ticket.CapErrors, Limit: opts.maxErrors}
+	}
+	return nil
+}
+
+func (opts *options) lines() (*ticket.LineOptions, error) {
+	lines := &ticket.LineOptions{MaxSize: opts.maxLine}
+
+	var err error
+	if opts.longLines != "" {
+		if lines.LongLines, err = ticket.ParseLongLinePolicy(opts.longLines); err != nil {
+			return nil, err
+		}
+	}
+	if opts.split != "" {
+		if lines.Split, err = ticket.NewSplitFunc(opts.split); err != nil {
+			return nil, err
+		}
+	}
+
+	return lines, nil
+}
+
+func openInput(name string, stdin io.Reader) (io.Reader, func() error, error) {
+	if name == "-" {
+		return stdin, func() error { return nil }, nil
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, file.Close, nil
+}
+
+func exitCode(err error) int {
+	var rejection ticket.Rejection
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, context.Canceled):
+		return ExitFailure
+	case errors.As(err, &rejection), errors.Is(err, ticket.ErrTooManyRejections):
+		return ExitParse
+	}
+	return ExitRead
+}
+
+func runServe(ctx context.Context, args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tickets serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	addr := flags.String("addr", ":8080", "address to listen on")
+	logFile := flags.String("log", "", "ticket log served by GET /tickets")
+	timeout := flags.Duration("timeout", 10*time.Second, "maximum duration of a request, 0 means no limit")
+	maxBody := flags.Int64("max-body", DefaultMaxBody, "maximum size of a POST body in bytes")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	_, start, stop := NewServer(*addr, *logFile, *timeout, *maxBody)
+
+	errs := make(chan error, 1)
+	go func() { errs <- start() }()
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			fmt.Fprintf(stderr, "tickets: %v\n", err)
+			return ExitFailure
+		}
+	case <-ctx.Done():
+		if err := stop(); err != nil {
+			fmt.Fprintf(stderr, "tickets: %v\n", err)
+			return ExitFailure
+		}
+	}
+
+	return ExitOK
+}
+
+// runFollow печатает тикеты файла и затем новые по мере появления: JSON по одному объекту на строку или CSV.
+// Отмена и истечение -timeout — штатное завершение слежения.
+func runFollow(ctx context.Context, opts *options, decoder ticket.TicketDecoder, filter ticket.Filter, lines *ticket.LineOptions, stdout io.Writer) error {
+	encoder := json.NewEncoder(stdout)
+	write := func(t *ticket.Ticket) error { return encoder.Encode(t) }
+
+	if opts.output == "csv" {
+		writer := csv.NewWriter(stdout)
+		if err := writer.Write([]string{"ticket", "user", "status", "date"}); err != nil {
+			return err
+		}
+		writer.Flush()
+		write = func(t *ticket.Ticket) error {
+			if err := writer.Write([]string{t.Ticket, t.User, t.Status, t.Date.Format(ticket.DateLayout)}); err != nil {
+				return err
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	options := &ticket.FollowOptions{Interval: opts.interval, FromStart: true, Diagnostics: opts.diagnostics(), Lines: lines}
+	err := ticket.FollowTickets(ctx, opts.files[0], decoder, filter, options, write)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	fail := func(code int, err error) int {
+		fmt.Fprintf(stderr, "tickets: %v\n", err)
+		return code
+	}
+
+	if len(args) > 0 && args[0] == "serve" {
+		return runServe(ctx, args[1:], stderr)
+	}
+
+	opts, err := parseOptions(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	if err != nil {
+		return ExitUsage
+	}
+
+	if !slices.Contains(ticket.OutputFormats, opts.output) {
+		return fail(ExitUsage, fmt.Errorf("%v %w", opts.output, ticket.ErrNotOutput))
+	}
+
+	if opts.statuses != "" {
+		if err := ticket.Statuses.LoadFile(opts.statuses); err != nil {
+			return fail(ExitUsage, err)
+		}
+	}
+
+	decoder, err := ticket.NewTicketDecoder(opts.format)
+	if err != nil {
+		return fail(ExitUsage, err)
+	}
+
+	dates, err := opts.dates()
+	if err != nil {
+		return fail(ExitUsage, err)
+	}
+	decoder = ticket.WithDates(decoder, dates)
+
+	filter, err := opts.filter()
+	if err != nil {
+		return fail(ExitUsage, err)
+	}
+
+	lines, err := opts.lines()
+	if err != nil {
+		return fail(ExitUsage, err)
+	}
+
+	var cancel context.CancelFunc
+	if opts.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	if opts.follow {
+		if len(opts.files) != 1 || opts.files[0] == "-" || (opts.output != "json" && opts.output != "csv") {
+			return fail(ExitUsage, errors.New("-follow needs exactly one file and json or csv output"))
+		}
+		if err := runFollow(ctx, opts, decoder, filter, lines, stdout); err != nil {
+			return fail(exitCode(err), err)
+		}
+		return ExitOK
+	}
+
+	if opts.index != "" {
+		if len(opts.files) != 1 || opts.files[0] == "-" {
+			return fail(ExitUsage, errors.New("-index needs exactly one file"))
+		}
+		if opts.strict || opts.maxErrors != -1 || opts.dedup != "" || !lines.IsDefault() {
+			return fail(ExitUsage, errors.New("-index cannot be used with -strict, -max-errors, -dedup, -max-line, -long-lines or -split"))
+		}
+		query, err := opts.query()
+		if err != nil {
+			return fail(ExitUsage, err)
+		}
+
+		tickets, err := ticket.QueryIndex(ctx, opts.files[0], opts.index, decoder, query, filter)
+		if err != nil {
+			return fail(exitCode(err), fmt.Errorf("%v: %w", opts.files[0], err))
+		}
+		if err := ticket.WriteTickets(stdout, tickets, opts.output); err != nil {
+			return fail(ExitFailure, err)
+		}
+		return ExitOK
+	}
+
+	var dedup *ticket.Dedup
+	if opts.dedup != "" {
+		key, err := ticket.ParseDedupKey(opts.dedup)
+		if err != nil {
+			return fail(ExitUsage, err)
+		}
+		dedup = ticket.NewDedup(key)
+	}
+
+	diagnostics := opts.diagnostics()
+
+	tickets := make([]ticket.Ticket, 0)
+	for _, name := range opts.files {
+		r, closeInput, err := openInput(name, stdin)
+		if err != nil {
+			return fail(ExitRead, err)
+		}
+
+		// Повторы ищутся до фильтра, чтобы конфликт не скрылся за условием на статус.
+		err = ticket.ScanTicketLines(ctx, r, lines, decoder, diagnostics, func(line ticket.TicketLine) error {
+			if dedup != nil && !dedup.Add(*line.Ticket, ticket.SourceLine{Source: name, Line: line.Number, Text: line.Text}) {
+				return nil
+			}
+			if filter.Match(line.Ticket) {
+				tickets = append(tickets, *line.Ticket)
+			}
+			return nil
+		})
+		closeInput()
+		if err != nil {
+			return fail(exitCode(err), fmt.Errorf("%v: %w", name, err))
+		}
+	}
+
+	if err := ticket.WriteTickets(stdout, tickets, opts.output); err != nil {
+		return fail(ExitFailure, err)
+	}
+
+	if dedup != nil && len(dedup.Conflicts) > 0 {
+		for _, conflict := range dedup.Conflicts {
+			fmt.Fprintf(stderr, "tickets: %v\n", conflict)
+		}
+		return ExitConflict
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "first.log")
+	_ = os.WriteFile(first, []byte(strings.Join([]string{
+		"TICKET-1_alice_Готово_2026-01-02",
+		"TICKET-2_bob_В работе_2026-01-03",
+		"some-text",
+	}, "\n")), 0666)
+
+	second := filepath.Join(dir, "second.csv")
+	_ = os.WriteFile(second, []byte(strings.Join([]string{
+		"TICKET-3,alice,В работе,2026-01-04",
+		"TICKET-4,bob,Готово,2026-01-05",
+	}, "\n")), 0666)
+
+	statuses := filepath.Join(dir, "statuses.conf")
+	_ = os.WriteFile(statuses, []byte("# German export\nГотово = Erledigt\n"), 0666)
+
+	var tests = []struct {
+		name     string
+		args     []string
+		stdin    io.Reader
+		expected string
+		code     int
+	}{
+		{
+			name:     "Case stdin",
+			args:     []string{"--user", "alice", "--output", "csv"},
+			stdin:    strings.NewReader("TICKET-1_alice_Готово_2026-01-02\nTICKET-2_bob_Готово_2026-01-02\n"),
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\n",
+			code:     ExitOK,
+		},
+		{
+			name:     "Case file with status",
+			args:     []string{"-status", "В работе", "-output", "csv", first},
+			expected: "ticket,user,status,date\nTICKET-2,bob,В работе,2026-01-03\n",
+			code:     ExitOK,
+		},
+		{
+			name:     "Case since and until",
+			args:     []string{"--format", "csv", "--since", "2026-01-04", "--until", "2026-01-04", "--output", "csv", second},
+			expected: "ticket,user,status,date\nTICKET-3,alice,В работе,2026-01-04\n",
+			code:     ExitOK,
+		},
+		{
+			name:     "Case filter expression",
+			args:     []string{"--filter", "user = bob or ticket = TICKET-1", "--output", "json", first},
+			expected: `[{"Ticket":"TICKET-1","User":"alice","Status":"Готово","Date":"2026-01-02T00:00:00Z"},{"Ticket":"TICKET-2","User":"bob","Status":"В работе","Date":"2026-01-03T00:00:00Z"}]`,
+			code:     ExitOK,
+		},
+		{
+			name:  "Case stdin and file",
+			args:  []string{"--output", "csv", first, "-"},
+			stdin: strings.NewReader("TICKET-5_carol_Готово_2026-01-06"),
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\nTICKET-2,bob,В работе,2026-01-03\n" +
+				"TICKET-5,carol,Готово,2026-01-06\n",
+			code: ExitOK,
+		},
+		{
+			name:     "Case markdown",
+			args:     []string{"--output", "markdown", "--user", "bob", first},
+			expected: "## bob\n\n- [ ] TICKET-2 — В работе, 2026-01-03\n",
+			code:     ExitOK,
+		},
+		{
+			name: "Case follow ics",
+			args: []string{"--follow", "--output", "ics", first},
+			code: ExitUsage,
+		},
+		{
+			name:     "Case dedup",
+			args:     []string{"--dedup", "id+date", "--output", "csv", first, "-"},
+			stdin:    strings.NewReader("TICKET-1_alice_Готово_2026-01-02\n"),
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\nTICKET-2,bob,В работе,2026-01-03\n",
+			code:     ExitOK,
+		},
+		{
+			name:     "Case dedup conflict",
+			args:     []string{"--dedup", "id", "--status", "Готово", "--output", "csv", first, "-"},
+			stdin:    strings.NewReader("TICKET-2_bob_Готово_2026-01-04\n"),
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\nTICKET-2,bob,Готово,2026-01-04\n",
+			code:     ExitConflict,
+		},
+		{
+			name: "Case unknown dedup key",
+			args: []string{"--dedup", "user"},
+			code: ExitUsage,
+		},
+		{
+			name: "Case unknown flag",
+			args: []string{"--owner", "alice"},
+			code: ExitUsage,
+		},
+		{
+			name: "Case unknown output",
+			args: []string{"--output", "xml"},
+			code: ExitUsage,
+		},
+		{
+			name: "Case unknown input format",
+			args: []string{"--format", "xml"},
+			code: ExitUsage,
+		},
+		{
+			name: "Case invalid filter",
+			args: []string{"--filter", "user ="},
+			code: ExitUsage,
+		},
+		{
+			name: "Case invalid since",
+			args: []string{"--since", "yesterday"},
+			code: ExitUsage,
+		},
+		{
+			name: "Case missing file",
+			args: []string{filepath.Join(dir, "missing.log")},
+			code: ExitRead,
+		},
+		{
+			name: "Case read error",
+			stdin: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				n = copy(p, []byte("TICKET-1_alice_Готово_2026-01-02\n"))
+				return n, testutils.FakeReadError
+			}),
+			code: ExitRead,
+		},
+		{
+			name: "Case strict",
+			args: []string{"--strict", first},
+			code: ExitParse,
+		},
+		{
+			name: "Case max errors",
+			args: []string{"--max-errors", "0", first},
+			code: ExitParse,
+		},
+		{
+			name:     "Case max errors not reached",
+			args:     []string{"--max-errors", "1", "--output", "csv", "--user", "bob", first},
+			expected: "ticket,user,status,date\nTICKET-2,bob,В работе,2026-01-03\n",
+			code:     ExitOK,
+		},
+		{
+			name:     "Case long line skipped",
+			args:     []string{"--max-line", "40", "--long-lines", "skip", "--output", "csv"},
+			stdin:    strings.NewReader("TICKET-1_alice_Готово_2026-01-02 " + strings.Repeat("trace ", 10) + "\nTICKET-2_bob_Готово_2026-01-02\n"),
+			expected: "ticket,user,status,date\nTICKET-2,bob,Готово,2026-01-02\n",
+			code:     ExitOK,
+		},
+		{
+			name:  "Case long line",
+			args:  []string{"--max-line", "40"},
+			stdin: strings.NewReader("TICKET-1_alice_Готово_2026-01-02 " + strings.Repeat("trace ", 10) + "\n"),
+			code:  ExitRead,
+		},
+		{
+			name:     "Case nul split",
+			args:     []string{"--split", "nul", "--output", "csv"},
+			stdin:    strings.NewReader("TICKET-1_alice_Готово_2026-01-02\x00TICKET-2_bob_Готово_2026-01-02"),
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\nTICKET-2,bob,Готово,2026-01-02\n",
+			code:     ExitOK,
+		},
+		{
+			name: "Case unknown split",
+			args: []string{"--split", "words"},
+			code: ExitUsage,
+		},
+		{
+			name:     "Case status aliases",
+			args:     []string{"--statuses", statuses, "--status", "done", "--output", "csv"},
+			stdin:    strings.NewReader("TICKET-1_alice_ERLEDIGT_2026-01-02\nTICKET-2_bob_In progress_2026-01-02\n"),
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\n",
+			code:     ExitOK,
+		},
+		{
+			name:     "Case location",
+			args:     []string{"--location", "Europe/Moscow", "--since", "2026-01-02", "--output", "json"},
+			stdin:    strings.NewReader("TICKET-1_alice_Готово_2026-01-01T22:30:00Z\nTICKET-2_bob_Готово_2026-01-01T20:30:00Z\n"),
+			expected: `[{"Ticket":"TICKET-1","User":"alice","Status":"Готово","Date":"2026-01-02T01:30:00+03:00"}]`,
+			code:     ExitOK,
+		},
+		{
+			name:     "Case date layout",
+			args:     []string{"--date-layout", "02.01.2006", "--output", "csv"},
+			stdin:    strings.NewReader("TICKET-1_alice_Готово_02.01.2026\nTICKET-2_bob_Готово_2026-01-02\n"),
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\n",
+			code:     ExitOK,
+		},
+		{
+			name: "Case unknown location",
+			args: []string{"--location", "Mars/Olympus"},
+			code: ExitUsage,
+		},
+		{
+			name:     "Case follow",
+			args:     []string{"--follow", "--interval", "1ms", "--timeout", "20ms", "--user", "bob", first},
+			expected: `{"Ticket":"TICKET-2","User":"bob","Status":"В работе","Date":"2026-01-03T00:00:00Z"}` + "\n",
+			code:     ExitOK,
+		},
+		{
+			name: "Case follow stdin",
+			args: []string{"--follow"},
+			code: ExitUsage,
+		},
+		{
+			name:     "Case index",
+			args:     []string{"--index", filepath.Join(dir, "first.idx"), "--status", "В работе", "--output", "csv", first},
+			expected: "ticket,user,status,date\nTICKET-2,bob,В работе,2026-01-03\n",
+			code:     ExitOK,
+		},
+		{
+			name: "Case index without file",
+			args: []string{"--index", filepath.Join(dir, "stdin.idx")},
+			code: ExitUsage,
+		},
+		{
+			name: "Case index with strict",
+			args: []string{"--index", filepath.Join(dir, "strict.idx"), "--strict", first},
+			code: ExitUsage,
+		},
+		{
+			name: "Case index with dedup",
+			args: []string{"--index", filepath.Join(dir, "dedup.idx"), "--dedup", "id", first},
+			code: ExitUsage,
+		},
+		{
+			name:     "Case index with default reading options spelled differently",
+			args:     []string{"--index", filepath.Join(dir, "spelled.idx"), "--long-lines", "ERROR", "--split", " Lines", "--status", "В работе", "--output", "csv", first},
+			expected: "ticket,user,status,date\nTICKET-2,bob,В работе,2026-01-03\n",
+			code:     ExitOK,
+		},
+		{
+			name: "Case index with split",
+			args: []string{"--index", filepath.Join(dir, "split.idx"), "--split", "crlf", first},
+			code: ExitUsage,
+		},
+		{
+			name: "Case index with max line",
+			args: []string{"--index", filepath.Join(dir, "line.idx"), "--max-line", "10", "--max-errors", "1", first},
+			code: ExitUsage,
+		},
+		{
+			name: "Case missing statuses",
+			args: []string{"--statuses", filepath.Join(dir, "missing.conf")},
+			code: ExitUsage,
+		},
+		{
+			name: "Case timeout",
+			args: []string{"--timeout", "10ms"},
+			stdin: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				time.Sleep(20 * time.Millisecond)
+				n = copy(p, []byte("TICKET-1_alice_Готово_2026-01-02\n"))
+				return n, nil
+			}),
+			code: ExitTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdin := test.stdin
+			if stdin == nil {
+				stdin = strings.NewReader("")
+			}
+			stdout := bytes.NewBuffer(nil)
+			stderr := bytes.NewBuffer(nil)
+
+			code := Run(context.Background(), test.args, stdin, stdout, stderr)
+
+			if code != test.code {
+				t.Errorf("unexpected exit code: got %v, expected %v (%v)\n", code, test.code, stderr.String())
+			}
+			if got := stdout.String(); got != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
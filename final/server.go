@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/ticket"
+)
+
+// Server отдаёт тикеты по HTTP:
+//
+//	GET  /tickets?user=&status=&filter=  — из настроенного файла LogFile;
+//	POST /tickets?user=&status=&filter=  — из тела запроса.
+//
+// Timeout ограничивает каждый запрос; клиент может уменьшить его параметром timeout.
+// MaxBody ограничивает тело POST в байтах, 0 означает DefaultMaxBody; больше — 413.
+type Server struct {
+	LogFile string
+	Timeout time.Duration
+	MaxBody int64
+}
+
+const DefaultMaxBody = 10 << 20
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tickets", s.getTickets)
+	mux.HandleFunc("POST /tickets", s.postTickets)
+	return mux
+}
+
+func (s *Server) getTickets(w http.ResponseWriter, r *http.Request) {
+	if s.LogFile == "" {
+		http.Error(w, "log file is not configured", http.StatusNotFound)
+		return
+	}
+
+	file, err := os.Open(s.LogFile)
+	if err != nil {
+		log.Printf("error opening the log: %s\n", err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	s.serveTickets(w, r, file, http.StatusInternalServerError)
+}
+
+func (s *Server) postTickets(w http.ResponseWriter, r *http.Request) {
+	maxBody := s.MaxBody
+	if maxBody <= 0 {
+		maxBody = DefaultMaxBody
+	}
+	s.serveTickets(w, r, http.MaxBytesReader(w, r.Body, maxBody), http.StatusBadRequest)
+}
+
+func (s *Server) timeout(query url.Values) (time.Duration, error) {
+	timeout := s.Timeout
+
+	if value := query.Get("timeout"); value != "" {
+		requested, err := time.ParseDuration(value)
+		if err != nil || requested <= 0 {
+			return 0, fmt.Errorf("timeout %v is not a positive duration", value)
+		}
+		if timeout <= 0 || requested < timeout {
+			timeout = requested
+		}
+	}
+
+	return timeout, nil
+}
+
+func queryOptions(query url.Values) (*options, error) {
+	opts := &options{
+		user:      query.Get("user"),
+		status:    query.Get("status"),
+		expr:      query.Get("filter"),
+		since:     query.Get("since"),
+		until:     query.Get("until"),
+		format:    query.Get("format"),
+		maxErrors: -1,
+		longLines: query.Get("long-lines"),
+		split:     query.Get("split"),
+		location:  query.Get("location"),
+		layouts:   query["date-layout"],
+	}
+	if opts.format == "" {
+		opts.format = "underscore"
+	}
+
+	if value := query.Get("strict"); value != "" {
+		strict, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("strict %v is not a boolean", value)
+		}
+		opts.strict = strict
+	}
+
+	if value := query.Get("max-errors"); value != "" {
+		maxErrors, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("max-errors %v is not an integer", value)
+		}
+		opts.maxErrors = maxErrors
+	}
+
+	if value := query.Get("max-line"); value != "" {
+		maxLine, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("max-line %v is not an integer", value)
+		}
+		opts.maxLine = maxLine
+	}
+
+	return opts, nil
+}
+
+// serveTickets разбирает r и пишет отфильтрованные тикеты в JSON.
+// readStatus — код ответа для ошибок чтения: за тело запроса отвечает клиент, за файл — сервер.
+func (s *Server) serveTickets(w http.ResponseWriter, r *http.Request, body io.Reader, readStatus int) {
+	query := r.URL.Query()
+
+	opts, err := queryOptions(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	decoder, err := ticket.NewTicketDecoder(opts.format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dates, err := opts.dates()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	decoder = ticket.WithDates(decoder, dates)
+	filter, err := opts.filter()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lines, err := opts.lines()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	timeout, err := s.timeout(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if timeout <= 0 {
+		timeout = ticket.NoTimeout
+	}
+
+	response := &jsonResponse{w: w}
+	err = ticket.GetTasksWith(r.Context(), body, response, lines, decoder, filter, opts.diagnostics(), timeout)
+	if err != nil && response.written {
+		log.Printf("error writing the response: %s\n", err)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), httpStatus(err, readStatus))
+	}
+}
+
+// jsonResponse выставляет Content-Type перед первой записью, чтобы ошибку,
+// случившуюся до записи, ещё можно было отдать через http.Error.
+type jsonResponse struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (r *jsonResponse) Write(p []byte) (int, error) {
+	if !r.written {
+		r.written = true
+		r.w.Header().Set("Content-Type", "application/json")
+	}
+	return r.w.Write(p)
+}
+
+func httpStatus(err error, readStatus int) int {
+	var rejection ticket.Rejection
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	case errors.As(err, &rejection), errors.Is(err, ticket.ErrTooManyRejections), errors.Is(err, ticket.ErrParse):
+		return http.StatusUnprocessableEntity
+	}
+	return readStatus
+}
+
+// NewServer возвращает указатель на экземпляр http.Server и функции start, и stop, для запуска и остановки сервера.
+func NewServer(addr string, logFile string, timeout time.Duration, maxBody int64) (server *http.Server, start func() error, stop func() error) {
+	tickets := &Server{LogFile: logFile, Timeout: timeout, MaxBody: maxBody}
+	server = &http.Server{Addr: addr, Handler: tickets.Handler()}
+
+	start = func() error {
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+
+	stop = func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(ctx)
+	}
+
+	return server, start, stop
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+func TestServer(t *testing.T) {
+	var lines = []string{
+		"TICKET-1_alice_Готово_2026-01-02",
+		"TICKET-2_bob_В работе_2026-01-03",
+		"some-text",
+	}
+
+	logFile := filepath.Join(t.TempDir(), "tickets.log")
+	_ = os.WriteFile(logFile, []byte(strings.Join(lines, "\n")), 0666)
+
+	var (
+		alice = `[{"Ticket":"TICKET-1","User":"alice","Status":"Готово","Date":"2026-01-02T00:00:00Z"}]`
+		bob   = `[{"Ticket":"TICKET-2","User":"bob","Status":"В работе","Date":"2026-01-03T00:00:00Z"}]`
+	)
+
+	var tests = []struct {
+		name     string
+		server   *Server
+		method   string
+		target   string
+		body     io.Reader
+		code     int
+		expected string
+	}{
+		{
+			name:     "Case get by user",
+			server:   &Server{LogFile: logFile, Timeout: 100 * time.Millisecond},
+			method:   http.MethodGet,
+			target:   "/tickets?user=alice&status=",
+			code:     http.StatusOK,
+			expected: alice,
+		},
+		{
+			name:     "Case get by status",
+			server:   &Server{LogFile: logFile},
+			method:   http.MethodGet,
+			target:   "/tickets?status=" + "%D0%92%20%D1%80%D0%B0%D0%B1%D0%BE%D1%82%D0%B5",
+			code:     http.StatusOK,
+			expected: bob,
+		},
+		{
+			name:   "Case get without log",
+			server: &Server{},
+			method: http.MethodGet,
+			target: "/tickets",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "Case get missing log",
+			server: &Server{LogFile: filepath.Join(t.TempDir(), "missing.log")},
+			method: http.MethodGet,
+			target: "/tickets",
+			code:   http.StatusInternalServerError,
+		},
+		{
+			name:   "Case get strict",
+			server: &Server{LogFile: logFile},
+			method: http.MethodGet,
+			target: "/tickets?strict=true",
+			code:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Case post with filter",
+			server:   &Server{Timeout: 100 * time.Millisecond},
+			method:   http.MethodPost,
+			target:   "/tickets?filter=user+in+(bob,carol)",
+			body:     strings.NewReader(strings.Join(lines, "\n")),
+			code:     http.StatusOK,
+			expected: bob,
+		},
+		{
+			name:     "Case post csv",
+			server:   &Server{},
+			method:   http.MethodPost,
+			target:   "/tickets?format=csv",
+			body:     strings.NewReader("TICKET-1,alice,Готово,2026-01-02"),
+			code:     http.StatusOK,
+			expected: alice,
+		},
+		{
+			name:   "Case post invalid filter",
+			server: &Server{},
+			method: http.MethodPost,
+			target: "/tickets?filter=user+%3D",
+			body:   strings.NewReader(""),
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Case post invalid timeout",
+			server: &Server{},
+			method: http.MethodPost,
+			target: "/tickets?timeout=soon",
+			body:   strings.NewReader(""),
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Case post read error",
+			server: &Server{},
+			method: http.MethodPost,
+			target: "/tickets",
+			body: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				n = copy(p, []byte("TICKET-1_alice_Готово_2026-01-02\n"))
+				return n, testutils.FakeReadError
+			}),
+			code: http.StatusBadRequest,
+		},
+		{
+			name:   "Case post body too large",
+			server: &Server{MaxBody: 16},
+			method: http.MethodPost,
+			target: "/tickets",
+			body:   strings.NewReader("TICKET-1_alice_Готово_2026-01-02\n"),
+			code:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "Case post body within limit",
+			server:   &Server{MaxBody: 64},
+			method:   http.MethodPost,
+			target:   "/tickets",
+			body:     strings.NewReader("TICKET-1_alice_Готово_2026-01-02\n"),
+			code:     http.StatusOK,
+			expected: alice,
+		},
+		{
+			name:   "Case post timeout",
+			server: &Server{Timeout: time.Second},
+			method: http.MethodPost,
+			target: "/tickets?timeout=10ms",
+			body: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				time.Sleep(20 * time.Millisecond)
+				n = copy(p, []byte("TICKET-1_alice_Готово_2026-01-02\n"))
+				return n, nil
+			}),
+			code: http.StatusGatewayTimeout,
+		},
+		{
+			name:   "Case method not allowed",
+			server: &Server{},
+			method: http.MethodDelete,
+			target: "/tickets",
+			code:   http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(test.method, test.target, test.body)
+			recorder := httptest.NewRecorder()
+
+			start := time.Now()
+			test.server.Handler().ServeHTTP(recorder, request)
+			if duration := time.Since(start); duration > 100*time.Millisecond {
+				t.Errorf("unexpected execution time: got %v\n", duration)
+			}
+
+			if recorder.Code != test.code {
+				t.Errorf("unexpected status: got %v, expected %v (%v)\n", recorder.Code, test.code, recorder.Body.String())
+			}
+			if test.code == http.StatusOK && recorder.Body.String() != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", recorder.Body.String(), test.expected)
+			}
+			if got := recorder.Header().Get("Content-Type"); test.code == http.StatusOK && got != "application/json" {
+				t.Errorf("unexpected content type: got %v, expected %v\n", got, "application/json")
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNoPatterns   = errors.New("no patterns")
+	ErrEmptyPattern = errors.New("pattern is empty")
+)
+
+const bufSize = 4096
+
+// Match — найденное вхождение: номер и копия байт шаблона,
+// смещение первого байта вхождения от начала потока.
+type Match struct {
+	Index   int
+	Pattern []byte
+	Offset  int64
+}
+
+// out — номера шаблонов, заканчивающихся в этом состоянии.
+type node struct {
+	next [256]int32
+	out  []int
+}
+
+// Matcher ищет сразу несколько шаблонов за один проход (Ахо — Корасик).
+// Вхождения, в том числе перекрывающиеся, находятся в потоке любой длины
+// без возврата назад. Matcher неизменяем и безопасен для параллельного использования.
+type Matcher struct {
+	patterns [][]byte
+	nodes    []node
+}
+
+// NewMatcher строит автомат по шаблонам. Пустые шаблоны не допускаются.
+func NewMatcher(patterns ...[]byte) (*Matcher, error) {
+	if len(patterns) == 0 {
+		return nil, ErrNoPatterns
+	}
+
+	m := &Matcher{patterns: make([][]byte, len(patterns)), nodes: make([]node, 1)}
+	for i, pattern := range patterns {
+		if len(pattern) == 0 {
+			return nil, ErrEmptyPattern
+		}
+		m.patterns[i] = append([]byte(nil), pattern...)
+		m.insert(i, m.patterns[i])
+	}
+	m.link()
+	return m, nil
+}
+
+// insert добавляет шаблон в бор. Корень — состояние 0, поэтому
+// нулевой переход означает отсутствие ребра.
+func (m *Matcher) insert(index int, pattern []byte) {
+	state := int32(0)
+	for _, c := range pattern {
+		if m.nodes[state].next[c] == 0 {
+			m.nodes = append(m.nodes, node{})
+			m.nodes[state].next[c] = int32(len(m.nodes) - 1)
+		}
+		state = m.nodes[state].next[c]
+	}
+	m.nodes[state].out = append(m.nodes[state].out, index)
+}
+
+// link достраивает бор суффиксными ссылками до полного автомата.
+func (m *Matcher) link() {
+	fail := make([]int32, len(m.nodes))
+	queue := make([]int32, 0, len(m.nodes))
+	for c := range 256 {
+		if child := m.nodes[0].next[c]; child != 0 {
+			queue = append(queue, child)
+		}
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		m.nodes[state].out = append(m.nodes[state].out, m.nodes[fail[state]].out...)
+		for c := range 256 {
+			child := m.nodes[state].next[c]
+			if child == 0 {
+				m.nodes[state].next[c] = m.nodes[fail[state]].next[c]
+				continue
+			}
+			fail[child] = m.nodes[fail[state]].next[c]
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Patterns возвращает копии шаблонов в порядке их номеров.
+func (m *Matcher) Patterns() [][]byte {
+	patterns := make([][]byte, len(m.patterns))
+	for i, pattern := range m.patterns {
+		patterns[i] = bytes.Clone(pattern)
+	}
+	return patterns
+}
+
+// Stream — состояние поиска в одном потоке. Данные подаются частями
+// через Feed, вхождения на стыке частей не теряются.
+type Stream struct {
+	m      *Matcher
+	state  int32
+	offset int64
+}
+
+// Stream начинает новый поиск с нулевого смещения.
+func (m *Matcher) Stream() *Stream {
+	return &Stream{m: m}
+}
+
+// Offset возвращает число уже поданных байт.
+func (s *Stream) Offset() int64 {
+	return s.offset
+}
+
+// Feed подаёт очередную часть потока и вызывает fn для каждого вхождения,
+// закончившегося в p. Если fn возвращает false, Feed останавливается
+// сразу после этого вхождения и тоже возвращает false.
+func (s *Stream) Feed(p []byte, fn func(Match) bool) bool {
+	nodes := s.m.nodes
+	for i, c := range p {
+		s.state = nodes[s.state].next[c]
+		for _, index := range nodes[s.state].out {
+			pattern := s.m.patterns[index]
+			end := s.offset + int64(i) + 1
+			if !fn(Match{Index: index, Pattern: bytes.Clone(pattern), Offset: end - int64(len(pattern))}) {
+				s.offset += int64(i) + 1
+				return false
+			}
+		}
+	}
+	s.offset += int64(len(p))
+	return true
+}
+
+// Scan читает r до конца и вызывает fn для каждого вхождения в порядке
+// их окончания. Поиск останавливается, когда fn возвращает false.
+func (m *Matcher) Scan(r io.Reader, fn func(Match) bool) error {
+	s := m.Stream()
+	buf := make([]byte, bufSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 && !s.Feed(buf[:n], fn) {
+			return nil
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type chunk struct {
+	p   []byte
+	err error
+}
+
+// readChunks читает r в отдельной горутине. Буферов два: пока получатель
+// разбирает один, в другой читается следующая часть. Канал без буфера
+// гарантирует, что буфер не перезаписывается до получения следующей части.
+func readChunks(ctx context.Context, r io.Reader) <-chan chunk {
+	chunks := make(chan chunk)
+
+	go func() {
+		defer close(chunks)
+
+		bufs := [2][]byte{make([]byte, bufSize), make([]byte, bufSize)}
+		for i := 0; ; i ^= 1 {
+			n, err := r.Read(bufs[i])
+			select {
+			case <-ctx.Done():
+				return
+			case chunks <- chunk{p: bufs[i][:n], err: err}:
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return chunks
+}
+
+// ScanContext работает как Scan, но прекращает ждать r при отмене ctx
+// и возвращает ctx.Err(). Заблокированный Read завершается в фоне.
+func (m *Matcher) ScanContext(ctx context.Context, r io.Reader, fn func(Match) bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s := m.Stream()
+	chunks := readChunks(ctx, r)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c := <-chunks:
+			if len(c.p) > 0 && !s.Feed(c.p, fn) {
+				return nil
+			}
+			if c.err == io.EOF {
+				return nil
+			}
+			if c.err != nil {
+				return c.err
+			}
+		}
+	}
+}
+
+// First возвращает вхождение, которое заканчивается раньше остальных.
+func (m *Matcher) First(r io.Reader) (Match, bool, error) {
+	return first(func(fn func(Match) bool) error { return m.Scan(r, fn) })
+}
+
+// FirstContext работает как First с учётом ctx.
+func (m *Matcher) FirstContext(ctx context.Context, r io.Reader) (Match, bool, error) {
+	return first(func(fn func(Match) bool) error { return m.ScanContext(ctx, r, fn) })
+}
+
+func first(scan func(fn func(Match) bool) error) (Match, bool, error) {
+	var (
+		match Match
+		found bool
+	)
+	err := scan(func(m Match) bool {
+		match, found = m, true
+		return false
+	})
+	if err != nil {
+		return Match{}, false, err
+	}
+	return match, found, nil
+}
+
+// All возвращает все вхождения, включая перекрывающиеся.
+func (m *Matcher) All(r io.Reader) ([]Match, error) {
+	matches := make([]Match, 0)
+	err := m.Scan(r, func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// Contains сообщает, встречается ли seq в r.
+func Contains(r io.Reader, seq []byte) (bool, error) {
+	m, err := NewMatcher(seq)
+	if err != nil {
+		return false, err
+	}
+	_, found, err := m.First(r)
+	return found, err
+}
+
+// ContainsContext работает как Contains с учётом ctx.
+func ContainsContext(ctx context.Context, r io.Reader, seq []byte) (bool, error) {
+	m, err := NewMatcher(seq)
+	if err != nil {
+		return false, err
+	}
+	_, found, err := m.FirstContext(ctx, r)
+	return found, err
+}
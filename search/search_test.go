@@ -0,0 +1,302 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+func patterns(s ...string) [][]byte {
+	p := make([][]byte, 0, len(s))
+	for _, pattern := range s {
+		p = append(p, []byte(pattern))
+	}
+	return p
+}
+
+func TestNewMatcher(t *testing.T) {
+	var tests = []struct {
+		name        string
+		patterns    [][]byte
+		expectedErr error
+	}{
+		{name: "Case one pattern", patterns: patterns("abc")},
+		{name: "Case many patterns", patterns: patterns("he", "she", "his", "hers")},
+		{name: "Case no patterns", patterns: nil, expectedErr: ErrNoPatterns},
+		{name: "Case empty pattern", patterns: patterns("abc", ""), expectedErr: ErrEmptyPattern},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewMatcher(test.patterns...)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestMatcherAll(t *testing.T) {
+	type found struct {
+		Pattern string
+		Offset  int64
+	}
+
+	var tests = []struct {
+		name     string
+		patterns [][]byte
+		reader   io.Reader
+		expected []found
+	}{
+		{
+			name:     "Case overlapping prefix",
+			patterns: patterns("aab"),
+			reader:   strings.NewReader("aaab"),
+			expected: []found{{"aab", 1}},
+		},
+		{
+			name:     "Case overlapping matches",
+			patterns: patterns("aa"),
+			reader:   strings.NewReader("aaaa"),
+			expected: []found{{"aa", 0}, {"aa", 1}, {"aa", 2}},
+		},
+		{
+			name:     "Case classic dictionary",
+			patterns: patterns("he", "she", "his", "hers"),
+			reader:   strings.NewReader("ushers"),
+			expected: []found{{"she", 1}, {"he", 2}, {"hers", 2}},
+		},
+		{
+			name:     "Case match across reads",
+			patterns: patterns("abcabd", "cab"),
+			reader:   iotest.OneByteReader(strings.NewReader("xabcabcabdx")),
+			expected: []found{{"cab", 3}, {"cab", 6}, {"abcabd", 4}},
+		},
+		{
+			name:     "Case cyrillic bytes",
+			patterns: patterns("работе"),
+			reader:   iotest.HalfReader(strings.NewReader("В работе")),
+			expected: []found{{"работе", 3}},
+		},
+		{
+			name:     "Case no match",
+			patterns: patterns("abcd"),
+			reader:   strings.NewReader("ababacabcacbcbc"),
+			expected: []found{},
+		},
+		{
+			name:     "Case empty reader",
+			patterns: patterns("abc"),
+			reader:   strings.NewReader(""),
+			expected: []found{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := NewMatcher(test.patterns...)
+			if err != nil {
+				t.Fatalf("unexpected error: got %v, expected %v\n", err, nil)
+			}
+
+			matches, err := m.All(test.reader)
+			if err != nil {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, nil)
+			}
+
+			got := make([]found, 0, len(matches))
+			for _, match := range matches {
+				if string(match.Pattern) != string(m.Patterns()[match.Index]) {
+					t.Errorf("unexpected value: got %s, expected %s\n", match.Pattern, m.Patterns()[match.Index])
+				}
+				got = append(got, found{string(match.Pattern), match.Offset})
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestMatcherFirst(t *testing.T) {
+	m, _ := NewMatcher(patterns("b", "ab")...)
+
+	var tests = []struct {
+		name          string
+		reader        io.Reader
+		expected      Match
+		expectedFound bool
+		expectedErr   error
+	}{
+		{
+			name:          "Case found",
+			reader:        strings.NewReader("xxab"),
+			expected:      Match{Index: 1, Pattern: []byte("ab"), Offset: 2},
+			expectedFound: true,
+		},
+		{
+			name:   "Case not found",
+			reader: strings.NewReader("xxx"),
+		},
+		{
+			name:        "Case read error",
+			reader:      iotest.ErrReader(testutils.FakeReadError),
+			expectedErr: testutils.FakeReadError,
+		},
+		{
+			name:          "Case data with EOF",
+			reader:        iotest.DataErrReader(strings.NewReader("b")),
+			expected:      Match{Index: 0, Pattern: []byte("b"), Offset: 0},
+			expectedFound: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, found, err := m.First(test.reader)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if found != test.expectedFound {
+				t.Errorf("unexpected value: got %v, expected %v\n", found, test.expectedFound)
+			}
+			if found && !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestMatcherCopies(t *testing.T) {
+	m, _ := NewMatcher(patterns("ab")...)
+
+	m.Patterns()[0][0] = 'x'
+	match, found, err := m.First(strings.NewReader("ab"))
+	if err != nil || !found {
+		t.Fatalf("unexpected result: got %v %v, expected match\n", found, err)
+	}
+	match.Pattern[0] = 'x'
+
+	if got := string(m.Patterns()[0]); got != "ab" {
+		t.Errorf("unexpected value: got %s, expected %s\n", got, "ab")
+	}
+	if found, _ := Contains(strings.NewReader("ab"), []byte("ab")); !found {
+		t.Errorf("unexpected value: got %v, expected %v\n", found, true)
+	}
+	if _, found, _ := m.First(strings.NewReader("xab")); !found {
+		t.Errorf("unexpected value: got %v, expected %v\n", found, true)
+	}
+}
+
+func TestStreamFeed(t *testing.T) {
+	m, _ := NewMatcher(patterns("ab")...)
+	s := m.Stream()
+
+	offsets := make([]int64, 0)
+	collect := func(match Match) bool {
+		offsets = append(offsets, match.Offset)
+		return true
+	}
+
+	s.Feed([]byte("xa"), collect)
+	s.Feed([]byte("bab"), collect)
+	if s.Offset() != 5 {
+		t.Errorf("unexpected value: got %v, expected %v\n", s.Offset(), 5)
+	}
+	if !reflect.DeepEqual(offsets, []int64{1, 3}) {
+		t.Errorf("unexpected value: got %v, expected %v\n", offsets, []int64{1, 3})
+	}
+
+	stopped := s.Feed([]byte("abab"), func(Match) bool { return false })
+	if stopped || s.Offset() != 7 {
+		t.Errorf("unexpected value: got %v %v, expected %v %v\n", stopped, s.Offset(), false, 7)
+	}
+}
+
+func TestContainsContext(t *testing.T) {
+	var tests = []struct {
+		name        string
+		timeout     time.Duration
+		reader      io.Reader
+		seq         []byte
+		expected    bool
+		expectedErr error
+	}{
+		{
+			name:     "Case overlapping prefix",
+			reader:   strings.NewReader("aaab"),
+			seq:      []byte("aab"),
+			expected: true,
+		},
+		{
+			name:     "Case not found",
+			reader:   strings.NewReader("abcdefghijklmnopqrstuvwxyz"),
+			seq:      []byte("apqr"),
+			expected: false,
+		},
+		{
+			name:        "Case empty sequence",
+			reader:      strings.NewReader("abc"),
+			seq:         nil,
+			expectedErr: ErrEmptyPattern,
+		},
+		{
+			name:        "Case read error",
+			reader:      iotest.ErrReader(testutils.FakeReadError),
+			seq:         []byte("abc"),
+			expectedErr: testutils.FakeReadError,
+		},
+		{
+			name:    "Case endless reader",
+			timeout: 10 * time.Millisecond,
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				return copy(p, "a"), nil
+			}),
+			seq:         []byte("b"),
+			expectedErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "Case blocked reader",
+			timeout: 10 * time.Millisecond,
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				time.Sleep(50 * time.Millisecond)
+				return copy(p, "b"), nil
+			}),
+			seq:         []byte("b"),
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.Background(), func() {}
+			if test.timeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, test.timeout)
+			}
+			defer cancel()
+
+			got, err := ContainsContext(ctx, test.reader, test.seq)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if got != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
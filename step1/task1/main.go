@@ -3,9 +3,19 @@ package main
 import "io"
 
 func WriteString(s string, w io.Writer) error {
-	_, err := w.Write([]byte(s))
+	_, err := write(w, []byte(s))
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// write пишет p в w одним вызовом Write. Короткая запись без ошибки
+// нарушает контракт io.Writer и возвращается как io.ErrShortWrite.
+func write(w io.Writer, p []byte) (int, error) {
+	n, err := w.Write(p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"testing"
 
@@ -59,3 +60,15 @@ func TestWriteString(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteStringShortWrite(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+
+	err := WriteString("abcdef", testutils.NewShortWriter(b, 4))
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("WriteString() got error \"%v\", expected \"%v\"\n", err, io.ErrShortWrite)
+	}
+	if got := b.String(); got != "abcd" {
+		t.Errorf("WriteString() got \"%s\", expected \"%s\"\n", got, "abcd")
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("tee writer is closed")
+
+// DefaultRetries — число повторов для Retry, если Sink.Retries не задан.
+const DefaultRetries = 3
+
+// Policy определяет, что делать, когда запись в приёмник не удалась.
+type Policy int
+
+const (
+	// FailAll останавливает запись во все приёмники и возвращает ошибку.
+	FailAll Policy = iota
+	// Drop отключает упавший приёмник, остальные продолжают получать данные.
+	Drop
+	// Retry повторяет запись Retries раз, затем поступает как FailAll.
+	Retry
+)
+
+// Sink — приёмник TeeWriter.
+type Sink struct {
+	// Name попадает в отчёт об ошибках.
+	Name   string
+	W      io.Writer
+	Policy Policy
+	// Retries и Delay — число повторов и пауза между ними для Retry.
+	Retries int
+	Delay   time.Duration
+	// Buffer > 0 делает приёмник асинхронным: записи копируются в очередь
+	// из Buffer элементов и пишутся в отдельной горутине. Полная очередь
+	// блокирует Write, поэтому медленный приёмник не расходует память без предела.
+	Buffer int
+}
+
+func (s *Sink) retries() int {
+	if s.Policy != Retry {
+		return 0
+	}
+	if s.Retries <= 0 {
+		return DefaultRetries
+	}
+	return s.Retries
+}
+
+// Короткая запись без ошибки считается ошибкой io.ErrShortWrite.
+func (s *Sink) write(p []byte) (int, error) {
+	written := 0
+	var err error
+	for attempt := 0; attempt <= s.retries(); attempt++ {
+		if attempt > 0 && s.Delay > 0 {
+			time.Sleep(s.Delay)
+		}
+
+		var n int
+		n, err = write(s.W, p[written:])
+		written += n
+		if err == nil {
+			return written, nil
+		}
+	}
+	return written, err
+}
+
+// SinkError — ошибка приёмника Name.
+type SinkError struct {
+	Name    string
+	Err     error
+	Dropped bool
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("sink %s: %v", e.Name, e.Err)
+}
+
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+type sink struct {
+	Sink
+	dropped bool
+	queue   chan []byte
+	done    chan struct{}
+}
+
+// TeeWriter пишет одни и те же данные во все приёмники.
+// Close можно вызывать одновременно с Write: запись, ждущая места в очереди,
+// прерывается с ErrClosed. Одновременные вызовы Write между собой не упорядочены.
+type TeeWriter struct {
+	mu       sync.Mutex
+	sendMu   sync.RWMutex
+	closing  chan struct{}
+	sinks    []*sink
+	failures []SinkError
+	err      error
+	closed   bool
+}
+
+// NewTeeWriter создаёт TeeWriter и запускает горутины асинхронных приёмников.
+// Асинхронные приёмники требуют вызова Close.
+func NewTeeWriter(sinks ...Sink) *TeeWriter {
+	t := &TeeWriter{sinks: make([]*sink, 0, len(sinks)), closing: make(chan struct{})}
+	for _, s := range sinks {
+		state := &sink{Sink: s}
+		if s.Buffer > 0 {
+			state.queue = make(chan []byte, s.Buffer)
+			state.done = make(chan struct{})
+			go t.drain(state)
+		}
+		t.sinks = append(t.sinks, state)
+	}
+	return t
+}
+
+// После ошибки drain вычитывает очередь без записи, чтобы Write не заблокировался.
+func (t *TeeWriter) drain(s *sink) {
+	defer close(s.done)
+	for p := range s.queue {
+		if !t.active(s) {
+			continue
+		}
+		if _, err := s.write(p); err != nil {
+			t.fail(s, err)
+		}
+	}
+}
+
+func (t *TeeWriter) active(s *sink) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !s.dropped && t.err == nil
+}
+
+// Для FailAll и Retry ошибка приёмника становится ошибкой всего TeeWriter.
+func (t *TeeWriter) fail(s *sink, err error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s.dropped = true
+	failure := SinkError{Name: s.Name, Err: err, Dropped: s.Policy == Drop}
+	t.failures = append(t.failures, failure)
+	if failure.Dropped {
+		return nil
+	}
+	if t.err == nil {
+		t.err = &failure
+	}
+	return t.err
+}
+
+func (t *TeeWriter) state() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return ErrClosed
+	}
+	return t.err
+}
+
+// Write пишет p во все активные приёмники. Если приёмник с FailAll или Retry
+// упал — сейчас или раньше в асинхронной горутине, — возвращается *SinkError,
+// а дальнейшие записи не выполняются.
+func (t *TeeWriter) Write(p []byte) (int, error) {
+	return t.WriteContext(context.Background(), p)
+}
+
+// WriteContext работает как Write, но перестаёт ждать места в очереди
+// асинхронного приёмника при отмене ctx и возвращает ctx.Err().
+func (t *TeeWriter) WriteContext(ctx context.Context, p []byte) (int, error) {
+	t.sendMu.RLock()
+	defer t.sendMu.RUnlock()
+
+	if err := t.state(); err != nil {
+		return 0, err
+	}
+
+	for _, s := range t.sinks {
+		if !t.active(s) {
+			continue
+		}
+		if s.queue != nil {
+			select {
+			case s.queue <- bytes.Clone(p):
+			case <-t.closing:
+				return 0, ErrClosed
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+			continue
+		}
+
+		n, err := s.write(p)
+		if err != nil {
+			if errTee := t.fail(s, err); errTee != nil {
+				return n, errTee
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// Close дожидается, пока асинхронные приёмники допишут очереди,
+// и возвращает ошибку TeeWriter, если она была.
+func (t *TeeWriter) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrClosed
+	}
+	t.closed = true
+	close(t.closing)
+	t.mu.Unlock()
+
+	// Очереди закрываются, только когда ни один Write не может в них отправить.
+	t.sendMu.Lock()
+	for _, s := range t.sinks {
+		if s.queue != nil {
+			close(s.queue)
+		}
+	}
+	t.sendMu.Unlock()
+
+	for _, s := range t.sinks {
+		if s.queue != nil {
+			<-s.done
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Report возвращает ошибки приёмников в порядке их появления.
+func (t *TeeWriter) Report() []SinkError {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]SinkError(nil), t.failures...)
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+func TestTeeWriter(t *testing.T) {
+	var tests = []struct {
+		name             string
+		policy           Policy
+		failing          func() *testutils.ScriptWriter
+		expectedErr      error
+		expectedFailing  string
+		expectedHealthy  string
+		expectedFailures int
+	}{
+		{
+			name:            "Case all sinks healthy",
+			policy:          FailAll,
+			failing:         func() *testutils.ScriptWriter { return testutils.NewScriptWriter() },
+			expectedFailing: "abcdef",
+			expectedHealthy: "abcdef",
+		},
+		{
+			name:   "Case fail all",
+			policy: FailAll,
+			failing: func() *testutils.ScriptWriter {
+				return testutils.NewScriptWriter(testutils.WriteStep{N: 3}, testutils.WriteStep{Err: testutils.FakeWriteError})
+			},
+			expectedErr:      testutils.FakeWriteError,
+			expectedFailing:  "abc",
+			expectedHealthy:  "abc",
+			expectedFailures: 1,
+		},
+		{
+			name:   "Case drop failing sink",
+			policy: Drop,
+			failing: func() *testutils.ScriptWriter {
+				return testutils.NewScriptWriter(testutils.WriteStep{N: 3}, testutils.WriteStep{Err: testutils.FakeWriteError})
+			},
+			expectedFailing:  "abc",
+			expectedHealthy:  "abcdef",
+			expectedFailures: 1,
+		},
+		{
+			name:   "Case retry succeeds",
+			policy: Retry,
+			failing: func() *testutils.ScriptWriter {
+				return testutils.NewScriptWriter(testutils.WriteStep{N: 1, Err: testutils.FakeWriteError}, testutils.WriteStep{N: 1})
+			},
+			expectedFailing: "abcdef",
+			expectedHealthy: "abcdef",
+		},
+		{
+			name:   "Case retries exhausted",
+			policy: Retry,
+			failing: func() *testutils.ScriptWriter {
+				return testutils.NewScriptWriter(
+					testutils.WriteStep{Err: testutils.FakeWriteError},
+					testutils.WriteStep{Err: testutils.FakeWriteError},
+					testutils.WriteStep{Err: testutils.FakeWriteError},
+					testutils.WriteStep{Err: testutils.FakeWriteError},
+				)
+			},
+			expectedErr:      testutils.FakeWriteError,
+			expectedFailing:  "",
+			expectedHealthy:  "",
+			expectedFailures: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			failing := test.failing()
+			healthy := bytes.NewBuffer(nil)
+			tee := NewTeeWriter(
+				Sink{Name: "failing", W: failing, Policy: test.policy},
+				Sink{Name: "healthy", W: healthy},
+			)
+
+			var err error
+			for _, s := range []string{"abc", "def"} {
+				if err = WriteString(s, tee); err != nil {
+					break
+				}
+			}
+			if errClose := tee.Close(); !errors.Is(errClose, test.expectedErr) {
+				t.Errorf("Close() got error \"%v\", expected \"%v\"\n", errClose, test.expectedErr)
+			}
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("WriteString() got error \"%v\", expected \"%v\"\n", err, test.expectedErr)
+			}
+			if got := string(failing.Bytes()); got != test.expectedFailing {
+				t.Errorf("failing sink got \"%s\", expected \"%s\"\n", got, test.expectedFailing)
+			}
+			if got := healthy.String(); got != test.expectedHealthy {
+				t.Errorf("healthy sink got \"%s\", expected \"%s\"\n", got, test.expectedHealthy)
+			}
+
+			report := tee.Report()
+			if len(report) != test.expectedFailures {
+				t.Fatalf("Report() got %v, expected %d failures\n", report, test.expectedFailures)
+			}
+			for _, failure := range report {
+				if failure.Name != "failing" || failure.Dropped != (test.policy == Drop) {
+					t.Errorf("Report() got %+v, expected failing sink with policy %v\n", failure, test.policy)
+				}
+			}
+		})
+	}
+}
+
+func TestTeeWriterShortWrite(t *testing.T) {
+	healthy := bytes.NewBuffer(nil)
+	tee := NewTeeWriter(Sink{Name: "short", W: testutils.NewShortWriter(bytes.NewBuffer(nil), 1), Policy: Drop}, Sink{Name: "healthy", W: healthy})
+
+	if err := WriteString("abc", tee); err != nil {
+		t.Errorf("WriteString() got error \"%v\", expected \"%v\"\n", err, nil)
+	}
+	if report := tee.Report(); len(report) != 1 || !errors.Is(&report[0], io.ErrShortWrite) {
+		t.Errorf("Report() got %v, expected short write\n", report)
+	}
+	if got := healthy.String(); got != "abc" {
+		t.Errorf("healthy sink got \"%s\", expected \"%s\"\n", got, "abc")
+	}
+}
+
+func TestTeeWriterAsync(t *testing.T) {
+	slow := bytes.NewBuffer(nil)
+	fast := bytes.NewBuffer(nil)
+	tee := NewTeeWriter(
+		Sink{Name: "slow", W: testutils.NewDelayWriter(context.Background(), slow, 5*time.Millisecond), Buffer: 10},
+		Sink{Name: "fast", W: fast},
+	)
+
+	start := time.Now()
+	for _, s := range []string{"a", "b", "c", "d"} {
+		if err := WriteString(s, tee); err != nil {
+			t.Errorf("WriteString() got error \"%v\", expected \"%v\"\n", err, nil)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("unexpected duration: got %v, expected writes not to wait for async sink\n", elapsed)
+	}
+
+	if err := tee.Close(); err != nil {
+		t.Errorf("Close() got error \"%v\", expected \"%v\"\n", err, nil)
+	}
+	if slow.String() != "abcd" || fast.String() != "abcd" {
+		t.Errorf("sinks got \"%s\" and \"%s\", expected \"%s\"\n", slow.String(), fast.String(), "abcd")
+	}
+	if err := WriteString("e", tee); !errors.Is(err, ErrClosed) {
+		t.Errorf("WriteString() got error \"%v\", expected \"%v\"\n", err, ErrClosed)
+	}
+}
+
+func TestTeeWriterAsyncBounded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	tee := NewTeeWriter(Sink{Name: "blocked", W: testutils.NewBlockingWriter(ctx), Buffer: 1, Policy: Drop})
+
+	start := time.Now()
+	for _, s := range []string{"a", "b", "c"} {
+		_ = WriteString(s, tee)
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("unexpected duration: got %v, expected full queue to block\n", elapsed)
+	}
+
+	if err := tee.Close(); err != nil {
+		t.Errorf("Close() got error \"%v\", expected \"%v\"\n", err, nil)
+	}
+	if report := tee.Report(); len(report) != 1 || !errors.Is(&report[0], context.DeadlineExceeded) {
+		t.Errorf("Report() got %v, expected deadline exceeded\n", report)
+	}
+}
+
+func TestTeeWriterAsyncFailAll(t *testing.T) {
+	healthy := bytes.NewBuffer(nil)
+	tee := NewTeeWriter(
+		Sink{Name: "failing", W: testutils.NewFailAfterWriter(bytes.NewBuffer(nil), 0, testutils.FakeWriteError), Buffer: 1},
+		Sink{Name: "healthy", W: healthy},
+	)
+
+	_ = WriteString("a", tee)
+	if err := tee.Close(); !errors.Is(err, testutils.FakeWriteError) {
+		t.Errorf("Close() got error \"%v\", expected \"%v\"\n", err, testutils.FakeWriteError)
+	}
+
+	var sinkErr *SinkError
+	if err := tee.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Close() got error \"%v\", expected \"%v\"\n", err, ErrClosed)
+	}
+	if report := tee.Report(); len(report) != 1 || !errors.As(&report[0], &sinkErr) || sinkErr.Name != "failing" {
+		t.Errorf("Report() got %v, expected failing sink\n", report)
+	}
+}
+
+func TestTeeWriterWriteContext(t *testing.T) {
+	blocked, unblock := context.WithCancel(context.Background())
+	defer unblock()
+
+	tee := NewTeeWriter(Sink{Name: "blocked", W: testutils.NewBlockingWriter(blocked), Buffer: 1, Policy: Drop})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var err error
+	for _, s := range []string{"a", "b", "c"} {
+		if _, err = tee.WriteContext(ctx, []byte(s)); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WriteContext() got error \"%v\", expected \"%v\"\n", err, context.DeadlineExceeded)
+	}
+
+	unblock()
+	if err := tee.Close(); err != nil {
+		t.Errorf("Close() got error \"%v\", expected \"%v\"\n", err, nil)
+	}
+}
+
+func TestTeeWriterConcurrentClose(t *testing.T) {
+	blocked, unblock := context.WithCancel(context.Background())
+	defer unblock()
+
+	tee := NewTeeWriter(
+		Sink{Name: "blocked", W: testutils.NewBlockingWriter(blocked), Buffer: 1, Policy: Drop},
+		Sink{Name: "async", W: io.Discard, Buffer: 1},
+	)
+
+	errs := make(chan error, 10)
+	for range 10 {
+		go func() {
+			_, err := tee.Write([]byte("a"))
+			errs <- err
+		}()
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	closed := make(chan error, 1)
+	go func() { closed <- tee.Close() }()
+
+	for range 10 {
+		select {
+		case err := <-errs:
+			if err != nil && !errors.Is(err, ErrClosed) {
+				t.Errorf("Write() got error \"%v\", expected nil or \"%v\"\n", err, ErrClosed)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Write() is still blocked after Close()\n")
+		}
+	}
+
+	unblock()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close() got error \"%v\", expected \"%v\"\n", err, nil)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Close() is still blocked\n")
+	}
+}
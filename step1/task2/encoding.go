@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrNotEncoding = errors.New("is not an encoding")
+
+// Encoding — исходная кодировка данных для ReadStringLimit.
+type Encoding int
+
+const (
+	// UTF8 не перекодирует данные, только заменяет битые последовательности.
+	UTF8 Encoding = iota
+	UTF16LE
+	UTF16BE
+	Windows1251
+	KOI8R
+	// Auto определяет кодировку данных без BOM по их содержимому, см. detect.
+	Auto
+)
+
+var encodingNames = map[string]Encoding{
+	"utf-8":        UTF8,
+	"utf-16le":     UTF16LE,
+	"utf-16be":     UTF16BE,
+	"windows-1251": Windows1251,
+	"koi8-r":       KOI8R,
+	"auto":         Auto,
+}
+
+// ParseEncoding возвращает кодировку по имени, например "windows-1251".
+func ParseEncoding(s string) (Encoding, error) {
+	encoding, ok := encodingNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return UTF8, fmt.Errorf("%v %w", s, ErrNotEncoding)
+	}
+	return encoding, nil
+}
+
+// boms — метки порядка байт и кодировки, которые они задают.
+var boms = []struct {
+	bom      []byte
+	encoding Encoding
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, UTF8},
+	{[]byte{0xFF, 0xFE}, UTF16LE},
+	{[]byte{0xFE, 0xFF}, UTF16BE},
+}
+
+// stripBOM отрезает BOM и возвращает заданную им кодировку.
+// Без BOM возвращается fallback.
+func stripBOM(p []byte, fallback Encoding) ([]byte, Encoding) {
+	for _, b := range boms {
+		if bytes.HasPrefix(p, b.bom) {
+			return p[len(b.bom):], b.encoding
+		}
+	}
+	return p, fallback
+}
+
+// detect угадывает кодировку данных без BOM: корректный UTF-8 остаётся UTF-8;
+// если старшие байты пар почти все 0x00 или 0x04 (ASCII и кириллица), это UTF-16;
+// иначе однобайтовая кириллица. В Windows-1251 строчные буквы лежат в 0xE0–0xFF,
+// в KOI8-R — в 0xC0–0xDF, а в обычном тексте строчных больше, чем прописных.
+// На коротких текстах из одних прописных букв догадка может ошибиться.
+func detect(p []byte) Encoding {
+	if len(p) >= 2 && len(p)%2 == 0 {
+		even, odd := 0, 0
+		for i, c := range p {
+			if c != 0x00 && c != 0x04 {
+				continue
+			}
+			if i%2 == 0 {
+				even++
+			} else {
+				odd++
+			}
+		}
+		pairs := len(p) / 2
+		switch {
+		case odd*4 > pairs*3 && even*4 < pairs:
+			return UTF16LE
+		case even*4 > pairs*3 && odd*4 < pairs:
+			return UTF16BE
+		}
+	}
+
+	if utf8.Valid(p) {
+		return UTF8
+	}
+
+	lower, upper := 0, 0
+	for _, c := range p {
+		switch {
+		case c >= 0xE0:
+			lower++
+		case c >= 0xC0:
+			upper++
+		}
+	}
+	if upper > lower {
+		return KOI8R
+	}
+	return Windows1251
+}
+
+// decode перекодирует p в UTF-8. Неполные и неизвестные
+// последовательности заменяются на utf8.RuneError.
+func decode(p []byte, encoding Encoding) string {
+	switch encoding {
+	case UTF16LE, UTF16BE:
+		units := make([]uint16, 0, len(p)/2)
+		for i := 0; i+1 < len(p); i += 2 {
+			if encoding == UTF16LE {
+				units = append(units, uint16(p[i])|uint16(p[i+1])<<8)
+			} else {
+				units = append(units, uint16(p[i])<<8|uint16(p[i+1]))
+			}
+		}
+		s := string(utf16.Decode(units))
+		if len(p)%2 != 0 {
+			s += string(utf8.RuneError)
+		}
+		return s
+	case Windows1251:
+		return decode8bit(p, &windows1251)
+	case KOI8R:
+		return decode8bit(p, &koi8r)
+	default:
+		return strings.ToValidUTF8(string(p), string(utf8.RuneError))
+	}
+}
+
+// decode8bit перекодирует однобайтовую кодировку: байты до 0x80 — ASCII,
+// остальные берутся из таблицы.
+func decode8bit(p []byte, table *[128]rune) string {
+	var b strings.Builder
+	b.Grow(len(p) * 2)
+	for _, c := range p {
+		if c < 0x80 {
+			b.WriteByte(c)
+		} else {
+			b.WriteRune(table[c-0x80])
+		}
+	}
+	return b.String()
+}
+
+// windows1251 — символы Windows-1251 для байт 0x80–0xFF.
+var windows1251 = [128]rune{
+	0x0402, 0x0403, 0x201A, 0x0453, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x20AC, 0x2030, 0x0409, 0x2039, 0x040A, 0x040C, 0x040B, 0x040F,
+	0x0452, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0xFFFD, 0x2122, 0x0459, 0x203A, 0x045A, 0x045C, 0x045B, 0x045F,
+	0x00A0, 0x040E, 0x045E, 0x0408, 0x00A4, 0x0490, 0x00A6, 0x00A7,
+	0x0401, 0x00A9, 0x0404, 0x00AB, 0x00AC, 0x00AD, 0x00AE, 0x0407,
+	0x00B0, 0x00B1, 0x0406, 0x0456, 0x0491, 0x00B5, 0x00B6, 0x00B7,
+	0x0451, 0x2116, 0x0454, 0x00BB, 0x0458, 0x0405, 0x0455, 0x0457,
+	0x0410, 0x0411, 0x0412, 0x0413, 0x0414, 0x0415, 0x0416, 0x0417,
+	0x0418, 0x0419, 0x041A, 0x041B, 0x041C, 0x041D, 0x041E, 0x041F,
+	0x0420, 0x0421, 0x0422, 0x0423, 0x0424, 0x0425, 0x0426, 0x0427,
+	0x0428, 0x0429, 0x042A, 0x042B, 0x042C, 0x042D, 0x042E, 0x042F,
+	0x0430, 0x0431, 0x0432, 0x0433, 0x0434, 0x0435, 0x0436, 0x0437,
+	0x0438, 0x0439, 0x043A, 0x043B, 0x043C, 0x043D, 0x043E, 0x043F,
+	0x0440, 0x0441, 0x0442, 0x0443, 0x0444, 0x0445, 0x0446, 0x0447,
+	0x0448, 0x0449, 0x044A, 0x044B, 0x044C, 0x044D, 0x044E, 0x044F,
+}
+
+// koi8r — символы KOI8-R для байт 0x80–0xFF.
+var koi8r = [128]rune{
+	0x2500, 0x2502, 0x250C, 0x2510, 0x2514, 0x2518, 0x251C, 0x2524,
+	0x252C, 0x2534, 0x253C, 0x2580, 0x2584, 0x2588, 0x258C, 0x2590,
+	0x2591, 0x2592, 0x2593, 0x2320, 0x25A0, 0x2219, 0x221A, 0x2248,
+	0x2264, 0x2265, 0x00A0, 0x2321, 0x00B0, 0x00B2, 0x00B7, 0x00F7,
+	0x2550, 0x2551, 0x2552, 0x0451, 0x2553, 0x2554, 0x2555, 0x2556,
+	0x2557, 0x2558, 0x2559, 0x255A, 0x255B, 0x255C, 0x255D, 0x255E,
+	0x255F, 0x2560, 0x2561, 0x0401, 0x2562, 0x2563, 0x2564, 0x2565,
+	0x2566, 0x2567, 0x2568, 0x2569, 0x256A, 0x256B, 0x256C, 0x00A9,
+	0x044E, 0x0430, 0x0431, 0x0446, 0x0434, 0x0435, 0x0444, 0x0433,
+	0x0445, 0x0438, 0x0439, 0x043A, 0x043B, 0x043C, 0x043D, 0x043E,
+	0x043F, 0x044F, 0x0440, 0x0441, 0x0442, 0x0443, 0x0436, 0x0432,
+	0x044C, 0x044B, 0x0437, 0x0448, 0x044D, 0x0449, 0x0447, 0x044A,
+	0x042E, 0x0410, 0x0411, 0x0426, 0x0414, 0x0415, 0x0424, 0x0413,
+	0x0425, 0x0418, 0x0419, 0x041A, 0x041B, 0x041C, 0x041D, 0x041E,
+	0x041F, 0x042F, 0x0420, 0x0421, 0x0422, 0x0423, 0x0416, 0x0412,
+	0x042C, 0x042B, 0x0417, 0x0428, 0x042D, 0x0429, 0x0427, 0x042A,
+}
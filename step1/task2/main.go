@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"math"
 )
 
 func ReadString(r io.Reader) (string, error) {
@@ -24,3 +27,62 @@ func ReadString(r io.Reader) (string, error) {
 
 	return string(stringBytes), nil
 }
+
+var (
+	ErrTooLarge      = errors.New("string is too large")
+	ErrNegativeLimit = errors.New("is a negative limit")
+)
+
+// TooLargeError — данных в reader больше, чем Limit байт.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("%v: limit %d bytes", ErrTooLarge, e.Limit)
+}
+
+func (e *TooLargeError) Unwrap() error {
+	return ErrTooLarge
+}
+
+// ReadStringLimit читает r целиком, но не больше max байт: если данных больше,
+// чтение прекращается с *TooLargeError. BOM в начале отрезается и задаёт
+// кодировку, иначе данные считаются записанными в encoding, а для Auto она
+// угадывается по содержимому. Результат — UTF-8. Отрицательный max — ошибка ErrNegativeLimit.
+func ReadStringLimit(r io.Reader, max int64, encoding Encoding) (string, error) {
+	if max < 0 {
+		return "", fmt.Errorf("%v %w", max, ErrNegativeLimit)
+	}
+
+	stringBytes := make([]byte, 0)
+
+	buf := make([]byte, 512)
+	limit := max
+	if limit < math.MaxInt64 {
+		limit++
+	}
+	limited := io.LimitReader(r, limit)
+	for {
+		n, err := limited.Read(buf)
+		if n > 0 {
+			stringBytes = append(stringBytes, buf[:n]...)
+		}
+		if int64(len(stringBytes)) > max {
+			return "", &TooLargeError{Limit: max}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	stringBytes, encoding = stripBOM(stringBytes, encoding)
+	if encoding == Auto {
+		encoding = detect(stringBytes)
+	}
+	return decode(stringBytes, encoding), nil
+}
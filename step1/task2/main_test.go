@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"io"
+	"math"
 	"strings"
 	"testing"
 
@@ -44,3 +46,204 @@ func TestReadString(t *testing.T) {
 		})
 	}
 }
+
+func TestReadStringLimit(t *testing.T) {
+	var tests = []struct {
+		name        string
+		reader      io.Reader
+		max         int64
+		encoding    Encoding
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Case within limit",
+			reader:   strings.NewReader("abcdefg"),
+			max:      7,
+			expected: "abcdefg",
+		},
+		{
+			name:        "Case too large",
+			reader:      strings.NewReader("abcdefgh"),
+			max:         7,
+			expectedErr: ErrTooLarge,
+		},
+		{
+			name:        "Case negative limit",
+			reader:      strings.NewReader("abc"),
+			max:         -1,
+			expectedErr: ErrNegativeLimit,
+		},
+		{
+			name:     "Case zero limit",
+			reader:   strings.NewReader(""),
+			max:      0,
+			expected: "",
+		},
+		{
+			name: "Case endless reader",
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				return copy(p, "abc"), nil
+			}),
+			max:         1 << 10,
+			expectedErr: ErrTooLarge,
+		},
+		{
+			name:     "Case UTF-8 BOM",
+			reader:   strings.NewReader("\xef\xbb\xbfПривет, мир"),
+			max:      100,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case invalid UTF-8",
+			reader:   strings.NewReader("a\xffb"),
+			max:      100,
+			expected: "a�b",
+		},
+		{
+			name:     "Case UTF-16LE BOM",
+			reader:   strings.NewReader("\xff\xfe\x1f\x04\x40\x04\x38\x04\x32\x04\x35\x04\x42\x04\x2c\x00\x20\x00\x3c\x04\x38\x04\x40\x04"),
+			max:      100,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case UTF-16BE BOM overrides encoding",
+			reader:   strings.NewReader("\xfe\xff\x04\x1f\x04\x40\x04\x38\x04\x32\x04\x35\x04\x42\x00\x2c\x00\x20\x04\x3c\x04\x38\x04\x40"),
+			max:      100,
+			encoding: Windows1251,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case UTF-16LE odd length",
+			reader:   strings.NewReader("\x41\x00\x42"),
+			max:      100,
+			encoding: UTF16LE,
+			expected: "A�",
+		},
+		{
+			name:     "Case Windows-1251",
+			reader:   strings.NewReader("\xcf\xf0\xe8\xe2\xe5\xf2\x2c\x20\xec\xe8\xf0"),
+			max:      100,
+			encoding: Windows1251,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case KOI8-R",
+			reader:   strings.NewReader("\xf0\xd2\xc9\xd7\xc5\xd4\x2c\x20\xcd\xc9\xd2"),
+			max:      100,
+			encoding: KOI8R,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case max int64",
+			reader:   strings.NewReader("abc"),
+			max:      math.MaxInt64,
+			expected: "abc",
+		},
+		{
+			name:     "Case auto UTF-8",
+			reader:   strings.NewReader("Привет, мир"),
+			max:      100,
+			encoding: Auto,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case auto ASCII",
+			reader:   strings.NewReader("hello"),
+			max:      100,
+			encoding: Auto,
+			expected: "hello",
+		},
+		{
+			name:     "Case auto UTF-16LE",
+			reader:   strings.NewReader("\x1f\x04\x40\x04\x38\x04\x32\x04\x35\x04\x42\x04\x2c\x00\x20\x00\x3c\x04\x38\x04\x40\x04"),
+			max:      100,
+			encoding: Auto,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case auto UTF-16BE",
+			reader:   strings.NewReader("\x04\x1f\x04\x40\x04\x38\x04\x32\x04\x35\x04\x42\x00\x2c\x00\x20\x04\x3c\x04\x38\x04\x40"),
+			max:      100,
+			encoding: Auto,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case auto Windows-1251",
+			reader:   strings.NewReader("\xcf\xf0\xe8\xe2\xe5\xf2\x2c\x20\xec\xe8\xf0"),
+			max:      100,
+			encoding: Auto,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case auto KOI8-R",
+			reader:   strings.NewReader("\xf0\xd2\xc9\xd7\xc5\xd4\x2c\x20\xcd\xc9\xd2"),
+			max:      100,
+			encoding: Auto,
+			expected: "Привет, мир",
+		},
+		{
+			name:     "Case no BOM falls back to UTF-8",
+			reader:   strings.NewReader("\xcf\xf0\xe8"),
+			max:      100,
+			expected: "�",
+		},
+		{
+			name:        "Case read error",
+			reader:      testutils.NewFailAfterReader(strings.NewReader("abcdefg"), 3, testutils.FakeReadError),
+			max:         100,
+			expectedErr: testutils.FakeReadError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ReadStringLimit(test.reader, test.max, test.encoding)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("ReadStringLimit(%v) got error \"%v\", expected \"%v\"\n", test.reader, err, test.expectedErr)
+			}
+			if got != test.expected {
+				t.Errorf("ReadStringLimit(%v) got \"%s\", expected \"%v\"\n", test.reader, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestTooLargeError(t *testing.T) {
+	_, err := ReadStringLimit(strings.NewReader("abcdefgh"), 4, UTF8)
+
+	var tooLarge *TooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 4 {
+		t.Errorf("ReadStringLimit got error \"%v\", expected limit %d\n", err, 4)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	var tests = []struct {
+		name        string
+		s           string
+		expected    Encoding
+		expectedErr error
+	}{
+		{name: "Case windows-1251", s: "Windows-1251", expected: Windows1251},
+		{name: "Case koi8-r", s: " koi8-r ", expected: KOI8R},
+		{name: "Case auto", s: "auto", expected: Auto},
+		{name: "Case unknown", s: "cp866", expected: UTF8, expectedErr: ErrNotEncoding},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseEncoding(test.s)
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("ParseEncoding(%q) got error \"%v\", expected \"%v\"\n", test.s, err, test.expectedErr)
+			}
+			if got != test.expected {
+				t.Errorf("ParseEncoding(%q) got %v, expected %v\n", test.s, got, test.expected)
+			}
+		})
+	}
+}
@@ -1,14 +1,115 @@
 package main
 
 import (
+	"io"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type UpperWriter struct {
 	UpperString string
+	pending     []byte
 }
 
 func (w *UpperWriter) Write(p []byte) (n int, err error) {
-	w.UpperString = strings.ToUpper(string(p))
+	complete, tail := splitIncomplete(append(w.pending, p...))
+	w.pending = tail
+	w.UpperString += strings.ToUpper(string(complete))
 	return len(p), nil
 }
+
+// Flush дописывает в UpperString незаконченную последовательность как есть.
+func (w *UpperWriter) Flush() error {
+	w.UpperString += string(w.pending)
+	w.pending = nil
+	return nil
+}
+
+func (w *UpperWriter) Close() error {
+	return w.Flush()
+}
+
+// splitIncomplete отделяет от p незаконченную UTF-8 последовательность в конце,
+// которая может дополниться следующей записью.
+func splitIncomplete(p []byte) (complete []byte, tail []byte) {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return p[:i], append([]byte(nil), p[i:]...)
+			}
+			break
+		}
+	}
+	return p, nil
+}
+
+// MapWriter пишет в w текст, в котором каждая руна заменена mapping.
+// Руна, разрезанная между вызовами Write, собирается перед заменой,
+// байты, которые не являются UTF-8, передаются без изменений.
+type MapWriter struct {
+	w       io.Writer
+	mapping func(rune) rune
+	pending []byte
+	buf     []byte
+}
+
+func NewMapWriter(w io.Writer, mapping func(rune) rune) *MapWriter {
+	return &MapWriter{w: w, mapping: mapping}
+}
+
+func NewToUpperWriter(w io.Writer) *MapWriter {
+	return NewMapWriter(w, unicode.ToUpper)
+}
+
+func NewToLowerWriter(w io.Writer) *MapWriter {
+	return NewMapWriter(w, unicode.ToLower)
+}
+
+func NewToTitleWriter(w io.Writer) *MapWriter {
+	return NewMapWriter(w, unicode.ToTitle)
+}
+
+func (w *MapWriter) Write(p []byte) (n int, err error) {
+	complete, tail := splitIncomplete(append(w.pending, p...))
+
+	w.buf = w.buf[:0]
+	for len(complete) > 0 {
+		r, size := utf8.DecodeRune(complete)
+		if r == utf8.RuneError && size == 1 {
+			w.buf = append(w.buf, complete[0])
+		} else {
+			w.buf = utf8.AppendRune(w.buf, w.mapping(r))
+		}
+		complete = complete[size:]
+	}
+
+	if _, err := w.w.Write(w.buf); err != nil {
+		return 0, err
+	}
+	w.pending = tail
+	return len(p), nil
+}
+
+// Flush передаёт в w незаконченную последовательность как есть.
+func (w *MapWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	if _, err := w.w.Write(w.pending); err != nil {
+		return err
+	}
+	w.pending = nil
+	return nil
+}
+
+// Close вызывает Flush и закрывает w, если это io.Closer.
+func (w *MapWriter) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if closer, ok := w.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -1,6 +1,12 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
 
 func TestWriteByUpperWriter(t *testing.T) {
 	var tests = []struct {
@@ -50,3 +56,114 @@ func TestWriteByUpperWriter(t *testing.T) {
 		})
 	}
 }
+
+func TestUpperWriterManyWrites(t *testing.T) {
+	writer := UpperWriter{}
+	p := []byte("привет, world")
+	for _, part := range [][]byte{p[:1], p[1:7], p[7:]} {
+		if _, err := writer.Write(part); err != nil {
+			t.Errorf("UpperWriter.Write(%v) got error \"%v\"\n", part, err)
+		}
+	}
+	if writer.UpperString != "ПРИВЕТ, WORLD" {
+		t.Errorf("UpperWriter.Write() write \"%s\", expected \"%s\"\n", writer.UpperString, "ПРИВЕТ, WORLD")
+	}
+}
+
+func TestUpperWriterFlush(t *testing.T) {
+	writer := UpperWriter{}
+	p := []byte("при")
+	if _, err := writer.Write(append(p, "в"[0])); err != nil {
+		t.Errorf("UpperWriter.Write() got error \"%v\"\n", err)
+	}
+	if writer.UpperString != "ПРИ" {
+		t.Errorf("UpperWriter.Write() write \"%s\", expected \"%s\"\n", writer.UpperString, "ПРИ")
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Errorf("UpperWriter.Close() got error \"%v\"\n", err)
+	}
+	if expected := "ПРИ\xd0"; writer.UpperString != expected {
+		t.Errorf("UpperWriter.Close() write %q, expected %q\n", writer.UpperString, expected)
+	}
+	if err := writer.Flush(); err != nil || writer.UpperString != "ПРИ\xd0" {
+		t.Errorf("UpperWriter.Flush() write %q, expected no change\n", writer.UpperString)
+	}
+}
+
+func TestMapWriter(t *testing.T) {
+	var tests = []struct {
+		name          string
+		writer        func(w io.Writer) *MapWriter
+		parts         []string
+		expectedValue string
+	}{
+		{
+			name:          "Upper cyrillic split across writes",
+			writer:        NewToUpperWriter,
+			parts:         []string{"г\xd0", "\xbeтово"},
+			expectedValue: "ГОТОВО",
+		},
+		{
+			name:          "Lower byte by byte",
+			writer:        NewToLowerWriter,
+			parts:         []string{"\xd0", "\x92", " ", "Р", "А", "Б", "О", "Т", "Е"},
+			expectedValue: "в работе",
+		},
+		{
+			name:          "Title",
+			writer:        NewToTitleWriter,
+			parts:         []string{"ǆ", "abc"},
+			expectedValue: "ǅABC",
+		},
+		{
+			name:          "Custom mapping",
+			writer:        func(w io.Writer) *MapWriter { return NewMapWriter(w, func(r rune) rune { return r + 1 }) },
+			parts:         []string{"ab", "c"},
+			expectedValue: "bcd",
+		},
+		{
+			name:          "Invalid bytes kept",
+			writer:        NewToUpperWriter,
+			parts:         []string{"a\xffb"},
+			expectedValue: "A\xffB",
+		},
+		{
+			name:          "Trailing bytes flushed on close",
+			writer:        NewToUpperWriter,
+			parts:         []string{"ab\xd0"},
+			expectedValue: "AB\xd0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := bytes.NewBuffer(nil)
+			writer := test.writer(b)
+
+			for _, part := range test.parts {
+				gotN, err := writer.Write([]byte(part))
+				if err != nil {
+					t.Errorf("MapWriter.Write(%q) got error \"%v\"\n", part, err)
+				}
+				if gotN != len(part) {
+					t.Errorf("MapWriter.Write(%q) got %d, expected %d\n", part, gotN, len(part))
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Errorf("MapWriter.Close() got error \"%v\"\n", err)
+			}
+			if got := b.String(); got != test.expectedValue {
+				t.Errorf("MapWriter write %q, expected %q\n", got, test.expectedValue)
+			}
+		})
+	}
+}
+
+func TestMapWriterError(t *testing.T) {
+	writer := NewToUpperWriter(testutils.NewCustomWriter())
+
+	if n, err := writer.Write([]byte("abc")); err == nil || n != 0 {
+		t.Errorf("MapWriter.Write() got %d, \"%v\", expected error\n", n, err)
+	}
+}
@@ -1,24 +1,15 @@
 package main
 
 import (
+	"context"
 	"io"
+
+	"github.com/galiullindo/go-2-step-by-step/copier"
 )
 
+// Copy копирует из r в w не больше n байт, читая r до конца
+// этого предела, а не одним вызовом Read.
 func Copy(r io.Reader, w io.Writer, n uint) error {
-	buf := make([]byte, n)
-
-	nRead, err := r.Read(buf)
-	if err == io.EOF {
-	} else if err != nil {
-		return err
-	}
-
-	if nRead > 0 {
-		_, err := w.Write(buf[:nRead])
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	_, err := copier.Copy(context.Background(), io.LimitReader(r, int64(n)), w, nil)
+	return err
 }
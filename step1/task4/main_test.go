@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"testing"
+	"testing/iotest"
 
 	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
 )
@@ -65,6 +66,22 @@ func TestCopy(t *testing.T) {
 			expectedValue:  "abcdefghijklmnopqrstuvwxyz",
 			errWasExpected: false,
 		},
+		{
+			name:           "Chunked input need 100 bytes",
+			reader:         iotest.OneByteReader(bytes.NewReader([]byte("abcdefghijklmnopqrstuvwxyz"))),
+			writer:         bytes.NewBuffer([]byte(nil)),
+			n:              100,
+			expectedValue:  "abcdefghijklmnopqrstuvwxyz",
+			errWasExpected: false,
+		},
+		{
+			name:           "Chunked input need 10 bytes",
+			reader:         iotest.HalfReader(bytes.NewReader([]byte("abcdefghijklmnopqrstuvwxyz"))),
+			writer:         bytes.NewBuffer([]byte(nil)),
+			n:              10,
+			expectedValue:  "abcdefghij",
+			errWasExpected: false,
+		},
 		{
 			name:           "Read error",
 			reader:         testutils.NewCustomReader(),
@@ -3,46 +3,18 @@ package main
 import (
 	"errors"
 	"io"
+
+	"github.com/galiullindo/go-2-step-by-step/search"
 )
 
 var ErrSequenceLengthZero = errors.New("sequence length cannot be zero")
 
-func checkingForSequence(buf []byte, seq []byte, n int, lt *int) bool {
-	for i := 0; i < n; i++ {
-		if buf[i] == seq[*lt] {
-			*lt++
-			if *lt == len(seq) {
-				return true
-			}
-		} else {
-			*lt = 0
-		}
-	}
-	return false
-}
-
+// Contains сообщает, встречается ли seq в r.
+// Поиск ведётся автоматом search.Matcher, поэтому перекрывающиеся
+// префиксы ("aab" в "aaab") не теряются.
 func Contains(r io.Reader, seq []byte) (bool, error) {
-	isContains := false
-	lt := 0
-
 	if len(seq) == 0 {
 		return false, ErrSequenceLengthZero
 	}
-
-	buf := make([]byte, 16)
-	for !isContains {
-		n, err := r.Read(buf)
-		if n > 0 {
-			isContains = checkingForSequence(buf, seq, n, &lt)
-		}
-
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return false, err
-		}
-	}
-
-	return isContains, nil
+	return search.Contains(r, seq)
 }
@@ -43,6 +43,13 @@ var tests = []struct {
 		expected:  false,
 		wantError: false,
 	},
+	{
+		name:      "Reader with overlapping prefix",
+		reader:    strings.NewReader("aaab"),
+		seq:       []byte("aab"),
+		expected:  true,
+		wantError: false,
+	},
 	{
 		name:      "Read error",
 		reader:    testutils.NewCustomReader(),
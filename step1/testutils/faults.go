@@ -0,0 +1,334 @@
+package testutils
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// ReaderFunc превращает функцию в io.Reader.
+type ReaderFunc func(p []byte) (n int, err error)
+
+func (f ReaderFunc) Read(p []byte) (n int, err error) {
+	return f(p)
+}
+
+// WriterFunc превращает функцию в io.Writer.
+type WriterFunc func(p []byte) (n int, err error)
+
+func (f WriterFunc) Write(p []byte) (n int, err error) {
+	return f(p)
+}
+
+// FailAfterReader читает из r, но после n байт возвращает err.
+type FailAfterReader struct {
+	mu  sync.Mutex
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func NewFailAfterReader(r io.Reader, n int64, err error) *FailAfterReader {
+	return &FailAfterReader{r: r, n: n, err: err}
+}
+
+func (r *FailAfterReader) Read(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.n <= 0 {
+		return 0, r.err
+	}
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+	n, err = r.r.Read(p)
+	r.n -= int64(n)
+	return n, err
+}
+
+// FailAfterWriter пишет в w, но после n байт возвращает err.
+// Запись, пересекающая предел, записывается частично.
+type FailAfterWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func NewFailAfterWriter(w io.Writer, n int64, err error) *FailAfterWriter {
+	return &FailAfterWriter{w: w, n: n, err: err}
+}
+
+func (w *FailAfterWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.n <= 0 {
+		return 0, w.err
+	}
+	if int64(len(p)) <= w.n {
+		n, err = w.w.Write(p)
+		w.n -= int64(n)
+		return n, err
+	}
+
+	n, err = w.w.Write(p[:w.n])
+	w.n -= int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, w.err
+}
+
+// ShortReader отдаёт из r не больше max байт за вызов.
+type ShortReader struct {
+	r   io.Reader
+	max int
+}
+
+// NewShortReader паникует, если max не положителен: такой читатель никогда не продвинется.
+func NewShortReader(r io.Reader, max int) *ShortReader {
+	if max <= 0 {
+		panic("testutils: ShortReader max must be positive")
+	}
+	return &ShortReader{r: r, max: max}
+}
+
+func (r *ShortReader) Read(p []byte) (n int, err error) {
+	if len(p) > r.max {
+		p = p[:r.max]
+	}
+	return r.r.Read(p)
+}
+
+// ShortWriter пишет в w не больше max байт за вызов и, нарушая
+// контракт io.Writer, не возвращает ошибку о короткой записи.
+type ShortWriter struct {
+	w   io.Writer
+	max int
+}
+
+// NewShortWriter паникует, если max не положителен: такой писатель никогда не продвинется.
+func NewShortWriter(w io.Writer, max int) *ShortWriter {
+	if max <= 0 {
+		panic("testutils: ShortWriter max must be positive")
+	}
+	return &ShortWriter{w: w, max: max}
+}
+
+func (w *ShortWriter) Write(p []byte) (n int, err error) {
+	if len(p) > w.max {
+		p = p[:w.max]
+	}
+	return w.w.Write(p)
+}
+
+// DelayReader ждёт delay перед каждым чтением из r.
+// Если ctx завершается раньше, чтение не выполняется и возвращается ctx.Err().
+type DelayReader struct {
+	ctx   context.Context
+	r     io.Reader
+	delay time.Duration
+}
+
+func NewDelayReader(ctx context.Context, r io.Reader, delay time.Duration) *DelayReader {
+	return &DelayReader{ctx: ctx, r: r, delay: delay}
+}
+
+func (r *DelayReader) Read(p []byte) (n int, err error) {
+	if err := wait(r.ctx, r.delay); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// DelayWriter ждёт delay перед каждой записью в w.
+// Если ctx завершается раньше, запись не выполняется и возвращается ctx.Err().
+type DelayWriter struct {
+	ctx   context.Context
+	w     io.Writer
+	delay time.Duration
+}
+
+func NewDelayWriter(ctx context.Context, w io.Writer, delay time.Duration) *DelayWriter {
+	return &DelayWriter{ctx: ctx, w: w, delay: delay}
+}
+
+func (w *DelayWriter) Write(p []byte) (n int, err error) {
+	if err := wait(w.ctx, w.delay); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// BlockingReader блокирует каждое чтение до завершения ctx
+// и возвращает ctx.Err().
+type BlockingReader struct {
+	ctx context.Context
+}
+
+func NewBlockingReader(ctx context.Context) *BlockingReader {
+	return &BlockingReader{ctx: ctx}
+}
+
+func (r *BlockingReader) Read(p []byte) (n int, err error) {
+	<-r.ctx.Done()
+	return 0, r.ctx.Err()
+}
+
+// BlockingWriter блокирует каждую запись до завершения ctx
+// и возвращает ctx.Err().
+type BlockingWriter struct {
+	ctx context.Context
+}
+
+func NewBlockingWriter(ctx context.Context) *BlockingWriter {
+	return &BlockingWriter{ctx: ctx}
+}
+
+func (w *BlockingWriter) Write(p []byte) (n int, err error) {
+	<-w.ctx.Done()
+	return 0, w.ctx.Err()
+}
+
+// DataErrorReader отдаёт data и вместе с последней частью — err.
+// Дальнейшие чтения возвращают 0 и err.
+type DataErrorReader struct {
+	mu   sync.Mutex
+	data []byte
+	err  error
+}
+
+func NewDataErrorReader(data []byte, err error) *DataErrorReader {
+	return &DataErrorReader{data: data, err: err}
+}
+
+func (r *DataErrorReader) Read(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n = copy(p, r.data)
+	r.data = r.data[n:]
+	if len(r.data) > 0 {
+		return n, nil
+	}
+	return n, r.err
+}
+
+// DataErrorWriter пишет p в w целиком и возвращает err вместе с числом байт.
+type DataErrorWriter struct {
+	w   io.Writer
+	err error
+}
+
+func NewDataErrorWriter(w io.Writer, err error) *DataErrorWriter {
+	return &DataErrorWriter{w: w, err: err}
+}
+
+func (w *DataErrorWriter) Write(p []byte) (n int, err error) {
+	n, err = w.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, w.err
+}
+
+// ReadStep — результат одного вызова ScriptReader.Read.
+type ReadStep struct {
+	Data []byte
+	Err  error
+}
+
+// ScriptReader проигрывает шаги по одному на вызов Read. Если Data
+// не помещается в p, остаток отдаётся следующим вызовом, а Err — вместе
+// с последней частью. После последнего шага возвращается io.EOF.
+type ScriptReader struct {
+	mu    sync.Mutex
+	steps []ReadStep
+}
+
+func NewScriptReader(steps ...ReadStep) *ScriptReader {
+	return &ScriptReader{steps: steps}
+}
+
+func (r *ScriptReader) Read(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.steps) == 0 {
+		return 0, io.EOF
+	}
+
+	step := &r.steps[0]
+	n = copy(p, step.Data)
+	step.Data = step.Data[n:]
+	if len(step.Data) > 0 {
+		return n, nil
+	}
+
+	err = step.Err
+	r.steps = r.steps[1:]
+	return n, err
+}
+
+// WriteStep — результат одного вызова ScriptWriter.Write:
+// сколько байт принять и какую ошибку вернуть.
+type WriteStep struct {
+	N   int
+	Err error
+}
+
+// ScriptWriter проигрывает шаги по одному на вызов Write и сохраняет
+// принятые байты. После последнего шага принимает всё.
+type ScriptWriter struct {
+	mu    sync.Mutex
+	steps []WriteStep
+	buf   bytes.Buffer
+}
+
+// NewScriptWriter паникует, если N какого-либо шага отрицателен: Write не может принять меньше нуля байт.
+func NewScriptWriter(steps ...WriteStep) *ScriptWriter {
+	for _, step := range steps {
+		if step.N < 0 {
+			panic("testutils: ScriptWriter step N must not be negative")
+		}
+	}
+	return &ScriptWriter{steps: steps}
+}
+
+func (w *ScriptWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.steps) == 0 {
+		return w.buf.Write(p)
+	}
+
+	step := w.steps[0]
+	w.steps = w.steps[1:]
+	n = min(step.N, len(p))
+	w.buf.Write(p[:n])
+	return n, step.Err
+}
+
+// Bytes возвращает принятые байты.
+func (w *ScriptWriter) Bytes() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return bytes.Clone(w.buf.Bytes())
+}
@@ -0,0 +1,272 @@
+package testutils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReaders(t *testing.T) {
+	var tests = []struct {
+		name        string
+		reader      func() io.Reader
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Case reader func",
+			reader:   func() io.Reader { return ReaderFunc(func(p []byte) (int, error) { return copy(p, "abc"), io.EOF }) },
+			expected: "abc",
+		},
+		{
+			name:        "Case fail after n bytes",
+			reader:      func() io.Reader { return NewFailAfterReader(strings.NewReader("abcdef"), 4, FakeReadError) },
+			expected:    "abcd",
+			expectedErr: FakeReadError,
+		},
+		{
+			name:     "Case fail after more than data",
+			reader:   func() io.Reader { return NewFailAfterReader(strings.NewReader("abc"), 10, FakeReadError) },
+			expected: "abc",
+		},
+		{
+			name:     "Case short reads",
+			reader:   func() io.Reader { return NewShortReader(strings.NewReader("abcdef"), 1) },
+			expected: "abcdef",
+		},
+		{
+			name: "Case delayed reads",
+			reader: func() io.Reader {
+				return NewDelayReader(context.Background(), strings.NewReader("abc"), time.Millisecond)
+			},
+			expected: "abc",
+		},
+		{
+			name:        "Case data with error",
+			reader:      func() io.Reader { return NewDataErrorReader([]byte("abc"), FakeReadError) },
+			expected:    "abc",
+			expectedErr: FakeReadError,
+		},
+		{
+			name: "Case script",
+			reader: func() io.Reader {
+				return NewScriptReader(
+					ReadStep{Data: []byte("ab")},
+					ReadStep{Data: []byte("cd"), Err: nil},
+					ReadStep{Data: []byte("ef"), Err: FakeReadError},
+				)
+			},
+			expected:    "abcdef",
+			expectedErr: FakeReadError,
+		},
+		{
+			name:     "Case script ends with EOF",
+			reader:   func() io.Reader { return NewScriptReader(ReadStep{Data: []byte("abc")}) },
+			expected: "abc",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := io.ReadAll(test.reader())
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if string(got) != test.expected {
+				t.Errorf("unexpected value: got %q, expected %q\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestScriptReaderSteps(t *testing.T) {
+	r := NewScriptReader(
+		ReadStep{Data: []byte("abcd")},
+		ReadStep{Err: FakeReadError},
+		ReadStep{Data: []byte("e")},
+	)
+
+	p := make([]byte, 3)
+	var tests = []struct {
+		expected    string
+		expectedErr error
+	}{
+		{expected: "abc"},
+		{expected: "d"},
+		{expectedErr: FakeReadError},
+		{expected: "e"},
+		{expectedErr: io.EOF},
+	}
+
+	for _, test := range tests {
+		n, err := r.Read(p)
+		if string(p[:n]) != test.expected || err != test.expectedErr {
+			t.Errorf("unexpected value: got %q %v, expected %q %v\n", p[:n], err, test.expected, test.expectedErr)
+		}
+	}
+}
+
+func TestWriters(t *testing.T) {
+	var tests = []struct {
+		name        string
+		writer      func(b *bytes.Buffer) io.Writer
+		writes      []string
+		expected    string
+		expectedN   []int
+		expectedErr error
+	}{
+		{
+			name:      "Case writer func",
+			writer:    func(b *bytes.Buffer) io.Writer { return WriterFunc(b.Write) },
+			writes:    []string{"abc"},
+			expected:  "abc",
+			expectedN: []int{3},
+		},
+		{
+			name:        "Case fail after n bytes",
+			writer:      func(b *bytes.Buffer) io.Writer { return NewFailAfterWriter(b, 4, FakeReadError) },
+			writes:      []string{"abc", "def", "g"},
+			expected:    "abcd",
+			expectedN:   []int{3, 1, 0},
+			expectedErr: FakeReadError,
+		},
+		{
+			name:        "Case fail after negative n",
+			writer:      func(b *bytes.Buffer) io.Writer { return NewFailAfterWriter(b, -1, FakeReadError) },
+			writes:      []string{"abc"},
+			expected:    "",
+			expectedN:   []int{0},
+			expectedErr: FakeReadError,
+		},
+		{
+			name:      "Case short writes",
+			writer:    func(b *bytes.Buffer) io.Writer { return NewShortWriter(b, 2) },
+			writes:    []string{"abc", "d"},
+			expected:  "abd",
+			expectedN: []int{2, 1},
+		},
+		{
+			name:      "Case delayed writes",
+			writer:    func(b *bytes.Buffer) io.Writer { return NewDelayWriter(context.Background(), b, time.Millisecond) },
+			writes:    []string{"abc"},
+			expected:  "abc",
+			expectedN: []int{3},
+		},
+		{
+			name:        "Case data with error",
+			writer:      func(b *bytes.Buffer) io.Writer { return NewDataErrorWriter(b, FakeReadError) },
+			writes:      []string{"abc"},
+			expected:    "abc",
+			expectedN:   []int{3},
+			expectedErr: FakeReadError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := bytes.NewBuffer(nil)
+			w := test.writer(b)
+
+			var err error
+			for i, s := range test.writes {
+				var n int
+				n, err = w.Write([]byte(s))
+				if n != test.expectedN[i] {
+					t.Errorf("unexpected value: got %v, expected %v\n", n, test.expectedN[i])
+				}
+			}
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if got := b.String(); got != test.expected {
+				t.Errorf("unexpected value: got %q, expected %q\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestScriptWriter(t *testing.T) {
+	w := NewScriptWriter(WriteStep{N: 2}, WriteStep{N: 1, Err: FakeReadError})
+
+	var tests = []struct {
+		write       string
+		expectedN   int
+		expectedErr error
+	}{
+		{write: "abc", expectedN: 2},
+		{write: "cd", expectedN: 1, expectedErr: FakeReadError},
+		{write: "def", expectedN: 3},
+	}
+
+	for _, test := range tests {
+		n, err := w.Write([]byte(test.write))
+		if n != test.expectedN || err != test.expectedErr {
+			t.Errorf("unexpected value: got %v %v, expected %v %v\n", n, err, test.expectedN, test.expectedErr)
+		}
+	}
+	if got := string(w.Bytes()); got != "abcdef" {
+		t.Errorf("unexpected value: got %q, expected %q\n", got, "abcdef")
+	}
+}
+
+func TestDelayCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	r := NewDelayReader(ctx, strings.NewReader("abc"), time.Hour)
+	if n, err := r.Read(make([]byte, 3)); n != 0 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected value: got %v %v, expected %v %v\n", n, err, 0, context.DeadlineExceeded)
+	}
+	w := NewDelayWriter(ctx, bytes.NewBuffer(nil), time.Hour)
+	if n, err := w.Write([]byte("abc")); n != 0 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected value: got %v %v, expected %v %v\n", n, err, 0, context.DeadlineExceeded)
+	}
+}
+
+func TestShortInvalidMax(t *testing.T) {
+	var tests = []struct {
+		name   string
+		create func()
+	}{
+		{name: "Case reader zero", create: func() { NewShortReader(strings.NewReader("abc"), 0) }},
+		{name: "Case writer negative", create: func() { NewShortWriter(bytes.NewBuffer(nil), -1) }},
+		{name: "Case script writer negative", create: func() { NewScriptWriter(WriteStep{N: 1}, WriteStep{N: -1}) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("unexpected value: got no panic, expected panic\n")
+				}
+			}()
+			test.create()
+		})
+	}
+}
+
+func TestBlocking(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := NewBlockingReader(ctx).Read(make([]byte, 1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, context.DeadlineExceeded)
+	}
+	if _, err := NewBlockingWriter(ctx).Write([]byte("a")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("unexpected duration: got %v, expected at least %v\n", elapsed, 10*time.Millisecond)
+	}
+}
@@ -64,6 +64,14 @@ func TestContains(t *testing.T) {
 			expected:       false,
 			errWasExpected: false,
 		},
+		{
+			name:           "Case overlapping prefix",
+			timeout:        0,
+			reader:         bytes.NewReader([]byte("aaab")),
+			sequence:       []byte("aab"),
+			expected:       true,
+			errWasExpected: false,
+		},
 		{
 			name:           "Case empty sequence",
 			timeout:        0,
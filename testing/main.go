@@ -1,99 +1,53 @@
 package main
 
 import (
-	"errors"
-	"strings"
 	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/ticket"
 )
 
-type TicketStatus string
+type TicketStatus = ticket.Status
 
 const (
-	Ready         TicketStatus = "Готово"
-	InProgress    TicketStatus = "В работе"
-	WillNotBeDone TicketStatus = "Не будет сделано"
+	Ready         = ticket.Ready
+	InProgress    = ticket.InProgress
+	WillNotBeDone = ticket.WillNotBeDone
 )
 
-var ErrInvalidStatus = errors.New("invalid status")
+var ErrInvalidStatus = ticket.ErrNotStatus
 
 func IsStatus(status string) bool {
-	return status == string(Ready) || status == string(InProgress) || status == string(WillNotBeDone)
+	return ticket.IsStatus(status)
 }
 
-type Ticket struct {
-	Ticket string
-	User   string
-	Status string
-	Date   time.Time
-}
+type Ticket = ticket.Ticket
 
-func NewTicket(ticket string, user string, status string, date time.Time) (Ticket, error) {
-	if !IsStatus(status) {
-		return Ticket{}, ErrInvalidStatus
+func NewTicket(id string, user string, status string, date time.Time) (Ticket, error) {
+	t, err := ticket.NewTicket(id, user, status, date)
+	if err != nil {
+		return Ticket{}, err
 	}
-	return Ticket{Ticket: ticket, User: user, Status: status, Date: date}, nil
-
+	return *t, nil
 }
 
 func isTicket(message string) bool {
-	return strings.HasPrefix(message, "TICKET")
+	return ticket.IsTicket(message)
 }
 
-var ErrInvalidData = errors.New("invalid data")
+var ErrInvalidData = ticket.ErrParse
 
 func parseMessageToTicket(message string) (Ticket, error) {
-	data := strings.Split(message, "_")
-	if len(data) != 4 {
-		return Ticket{}, ErrInvalidData
-	}
-
-	ticket := data[0]
-	user := data[1]
-	status := data[2]
-
-	date, err := time.Parse("2006-01-02", data[3])
+	t, err := ticket.DefaultDecoder.Decode(message)
 	if err != nil {
 		return Ticket{}, err
 	}
-
-	newticket, err := NewTicket(ticket, user, status, date)
-	if err != nil {
-		return Ticket{}, err
-	}
-
-	return newticket, err
+	return *t, nil
 }
 
-func checkTicketParams(ticket *Ticket, user *string, status *string) bool {
-	if user != nil && *user != ticket.User {
-		return false
-	}
-	if status != nil && *status != ticket.Status {
-		return false
-	}
-
-	return true
+func checkTicketParams(t *Ticket, user *string, status *string) bool {
+	return t.IsTarget(user, status)
 }
 
 func GetTasks(text string, user *string, status *string) []Ticket {
-	tickets := []Ticket{}
-
-	messages := strings.Split(text, "\n")
-	for _, message := range messages {
-		message = strings.TrimSpace(message)
-		if !isTicket(message) {
-			continue
-		}
-
-		ticket, err := parseMessageToTicket(message)
-		if err != nil {
-			continue
-		}
-
-		if checkTicketParams(&ticket, user, status) {
-			tickets = append(tickets, ticket)
-		}
-	}
-
-	return tickets
+	return ticket.GetTasksText(text, user, status)
 }
@@ -0,0 +1,233 @@
+package ticket
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrUnknownFormat = errors.New("is not a ticket format")
+
+const DateLayout = "2006-01-02"
+
+// DefaultDateLayouts пробуются по порядку, если в DateParser не заданы свои.
+var DefaultDateLayouts = []string{DateLayout, time.RFC3339, "02.01.2006", "2006-01-02 15:04:05"}
+
+// DateParser разбирает дату тикета первым подошедшим макетом из Layouts.
+// Дата без смещения считается в Location (UTC, если не задан). Дата с явным смещением
+// переводится в Location, чтобы вывод и группировка по дням шли в одном поясе.
+type DateParser struct {
+	Layouts  []string
+	Location *time.Location
+}
+
+func (p DateParser) Parse(s string) (time.Time, error) {
+	layouts := p.Layouts
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts
+	}
+	location := p.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	for _, layout := range layouts {
+		if date, err := time.ParseInLocation(layout, s, location); err == nil {
+			return date.In(location), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%v %w", s, ErrParse)
+}
+
+func (p DateParser) identity() string {
+	layouts := p.Layouts
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts
+	}
+	location := p.Location
+	if location == nil {
+		location = time.UTC
+	}
+	return fmt.Sprintf("%q %v", layouts, location)
+}
+
+type TicketDecoder interface {
+	Decode(line string) (*Ticket, error)
+}
+
+var DefaultDecoder TicketDecoder = UnderscoreDecoder{Sep: "_"}
+
+var decoders = map[string]TicketDecoder{
+	"underscore": DefaultDecoder,
+	"csv":        CSVDecoder{Comma: ','},
+	"tsv":        CSVDecoder{Comma: '\t'},
+	"jsonl":      JSONLinesDecoder{},
+	"kv":         KeyValueDecoder{},
+}
+
+func NewTicketDecoder(name string) (TicketDecoder, error) {
+	decoder, found := decoders[strings.ToLower(strings.TrimSpace(name))]
+	if !found {
+		return nil, fmt.Errorf("%v %w", name, ErrUnknownFormat)
+	}
+	return decoder, nil
+}
+
+func DecoderNames() []string {
+	names := make([]string, 0, len(decoders))
+	for name := range decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithDates возвращает копию встроенного декодера с другими правилами разбора дат.
+// Прочие декодеры возвращаются без изменений.
+func WithDates(decoder TicketDecoder, dates DateParser) TicketDecoder {
+	switch d := decoder.(type) {
+	case UnderscoreDecoder:
+		d.Dates = dates
+		return d
+	case CSVDecoder:
+		d.Dates = dates
+		return d
+	case JSONLinesDecoder:
+		d.Dates = dates
+		return d
+	case KeyValueDecoder:
+		d.Dates = dates
+		return d
+	}
+	return decoder
+}
+
+func parseFields(ticket string, user string, status string, date string, dates DateParser) (*Ticket, error) {
+	theDate, err := dates.Parse(date)
+	if err != nil {
+		return nil, err
+	}
+	return NewTicket(ticket, user, status, theDate)
+}
+
+type UnderscoreDecoder struct {
+	Sep   string
+	Dates DateParser
+}
+
+func (d UnderscoreDecoder) Decode(line string) (*Ticket, error) {
+	p := strings.Split(line, d.Sep)
+	if l := len(p); l != 4 {
+		return nil, fmt.Errorf("%d piece %w", l, ErrParse)
+	}
+
+	return parseFields(p[0], p[1], p[2], p[3], d.Dates)
+}
+
+type CSVDecoder struct {
+	Comma rune
+	Dates DateParser
+}
+
+func (d CSVDecoder) Decode(line string) (*Ticket, error) {
+	reader := csv.NewReader(strings.NewReader(line))
+	reader.Comma = d.Comma
+	reader.LazyQuotes = true
+
+	record, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%v %w", err, ErrParse)
+	}
+	if l := len(record); l != 4 {
+		return nil, fmt.Errorf("%d piece %w", l, ErrParse)
+	}
+
+	return parseFields(record[0], record[1], record[2], record[3], d.Dates)
+}
+
+type JSONLinesDecoder struct {
+	Dates DateParser
+}
+
+func (d JSONLinesDecoder) Decode(line string) (*Ticket, error) {
+	var record struct {
+		Ticket string
+		User   string
+		Status string
+		Date   string
+	}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, fmt.Errorf("%v %w", err, ErrParse)
+	}
+
+	return parseFields(record.Ticket, record.User, record.Status, record.Date, d.Dates)
+}
+
+// KeyValueDecoder разбирает строки вида: ticket=TICKET-1 user=bob status="В работе" date=2026-01-02
+type KeyValueDecoder struct {
+	Dates DateParser
+}
+
+func (d KeyValueDecoder) Decode(line string) (*Ticket, error) {
+	pairs, err := parseKeyValue(line)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{"ticket", "user", "status", "date"} {
+		if _, found := pairs[key]; !found {
+			return nil, fmt.Errorf("missing %v %w", key, ErrParse)
+		}
+	}
+
+	return parseFields(pairs["ticket"], pairs["user"], pairs["status"], pairs["date"], d.Dates)
+}
+
+func parseKeyValue(line string) (map[string]string, error) {
+	pairs := make(map[string]string)
+
+	rest := strings.TrimSpace(line)
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("%v %w", rest, ErrParse)
+		}
+		key := strings.ToLower(rest[:eq])
+		if strings.ContainsFunc(key, unicode.IsSpace) {
+			return nil, fmt.Errorf("%v %w", key, ErrParse)
+		}
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("%v %w", rest, ErrParse)
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+
+		if rest != "" && !unicode.IsSpace(rune(rest[0])) {
+			return nil, fmt.Errorf("%v %w", rest, ErrParse)
+		}
+
+		pairs[key] = value
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+
+	return pairs, nil
+}
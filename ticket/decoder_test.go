@@ -0,0 +1,187 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTicketDecoder(t *testing.T) {
+	var tests = []struct {
+		name        string
+		format      string
+		expectedErr error
+	}{
+		{name: "Case underscore", format: "underscore"},
+		{name: "Case csv", format: "csv"},
+		{name: "Case tsv", format: "tsv"},
+		{name: "Case jsonl", format: "jsonl"},
+		{name: "Case key=value", format: "kv"},
+		{name: "Case sensitivity check", format: " CSV "},
+		{name: "Case unknown format", format: "xml", expectedErr: ErrUnknownFormat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewTicketDecoder(test.format)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if (got == nil) != (test.expectedErr != nil) {
+				t.Errorf("unexpected decoder for %v: got %v\n", test.format, got)
+			}
+		})
+	}
+}
+
+func TestTicketDecoders(t *testing.T) {
+	var (
+		ready      = &Ticket{Ticket: "TICKET-12345", User: "user", Status: "Готово", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}
+		inProgress = &Ticket{Ticket: "TICKET-12345", User: "user", Status: "В работе", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}
+	)
+
+	var tests = []struct {
+		name        string
+		format      string
+		s           string
+		expected    *Ticket
+		expectedErr error
+	}{
+		{name: "Case underscore", format: "underscore", s: "TICKET-12345_user_Готово_2026-01-03", expected: ready},
+		{name: "Case csv", format: "csv", s: "TICKET-12345,user,Готово,2026-01-03", expected: ready},
+		{name: "Case csv quoted", format: "csv", s: `TICKET-12345,user,"В работе",2026-01-03`, expected: inProgress},
+		{name: "Case csv pieces", format: "csv", s: "TICKET-12345,user,Готово", expectedErr: ErrParse},
+		{name: "Case csv header", format: "csv", s: "ticket,user,status,date", expectedErr: ErrParse},
+		{name: "Case tsv", format: "tsv", s: "TICKET-12345\tuser\tВ работе\t2026-01-03", expected: inProgress},
+		{name: "Case jsonl", format: "jsonl", s: `{"ticket":"TICKET-12345","user":"user","status":"Готово","date":"2026-01-03"}`, expected: ready},
+		{name: "Case jsonl invalid json", format: "jsonl", s: `{"ticket":`, expectedErr: ErrParse},
+		{name: "Case jsonl invalid status", format: "jsonl", s: `{"ticket":"TICKET-12345","user":"user","status":"?","date":"2026-01-03"}`, expectedErr: ErrNotStatus},
+		{name: "Case kv", format: "kv", s: `ticket=TICKET-12345 user=user status="В работе" date=2026-01-03`, expected: inProgress},
+		{name: "Case kv any order", format: "kv", s: `date=2026-01-03  status=Готово ticket=TICKET-12345 user=user`, expected: ready},
+		{name: "Case kv missing key", format: "kv", s: `ticket=TICKET-12345 user=user date=2026-01-03`, expectedErr: ErrParse},
+		{name: "Case kv broken quote", format: "kv", s: `ticket=TICKET-12345 user=user status="В работе date=2026-01-03`, expectedErr: ErrParse},
+		{name: "Case kv invalid ticket", format: "kv", s: `ticket=BUG-1 user=user status=Готово date=2026-01-03`, expectedErr: ErrNotTicket},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			decoder, err := NewTicketDecoder(test.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+
+			got, err := decoder.Decode(test.s)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestDateParser(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	var tests = []struct {
+		name        string
+		parser      DateParser
+		s           string
+		expected    time.Time
+		expectedErr error
+	}{
+		{name: "Case day", s: "2026-01-03", expected: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "Case rfc 3339 with offset", s: "2026-01-03T10:15:30.5+03:00", expected: time.Date(2026, 1, 3, 7, 15, 30, 5e8, time.UTC)},
+		{name: "Case dotted day", s: "03.01.2026", expected: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "Case default location", parser: DateParser{Location: moscow}, s: "2026-01-03 10:15:30", expected: time.Date(2026, 1, 3, 10, 15, 30, 0, moscow)},
+		{name: "Case offset converted to location", parser: DateParser{Location: moscow}, s: "2026-01-03T22:15:30Z", expected: time.Date(2026, 1, 4, 1, 15, 30, 0, moscow)},
+		{name: "Case custom layouts", parser: DateParser{Layouts: []string{"02/01/2006"}}, s: "03/01/2026", expected: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "Case not in custom layouts", parser: DateParser{Layouts: []string{"02/01/2006"}}, s: "2026-01-03", expectedErr: ErrParse},
+		{name: "Case invalid date", s: "2026-13-03", expectedErr: ErrParse},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.parser.Parse(test.s)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if !got.Equal(test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+			if _, offset := got.Zone(); err == nil {
+				if _, expected := test.expected.Zone(); offset != expected {
+					t.Errorf("unexpected offset: got %v, expected %v\n", offset, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestWithDates(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	expected := time.Date(2026, 1, 3, 0, 0, 0, 0, moscow)
+
+	for _, name := range DecoderNames() {
+		decoder, _ := NewTicketDecoder(name)
+		decoder = WithDates(decoder, DateParser{Layouts: []string{"02.01.2006"}, Location: moscow})
+
+		line := map[string]string{
+			"underscore": "TICKET-1_user_Готово_03.01.2026",
+			"csv":        "TICKET-1,user,Готово,03.01.2026",
+			"tsv":        "TICKET-1\tuser\tГотово\t03.01.2026",
+			"jsonl":      `{"ticket":"TICKET-1","user":"user","status":"Готово","date":"03.01.2026"}`,
+			"kv":         "ticket=TICKET-1 user=user status=Готово date=03.01.2026",
+		}[name]
+
+		got, err := decoder.Decode(line)
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v\n", name, err)
+			continue
+		}
+		if !got.Date.Equal(expected) {
+			t.Errorf("unexpected date for %v: got %v, expected %v\n", name, got.Date, expected)
+		}
+	}
+}
+
+func TestGetTasksWithDecoder(t *testing.T) {
+	var lines = []string{
+		"ticket,user,status,date",
+		"TICKET-12345,user,Готово,2026-01-02",
+		`TICKET-12346,user,"В работе",2026-01-03`,
+		"TICKET-12347_user_Готово_2026-01-04",
+	}
+
+	decoder, _ := NewTicketDecoder("csv")
+
+	w := bytes.NewBuffer(nil)
+	err := GetTasks(context.Background(), strings.NewReader(strings.Join(lines, "\n")), w, decoder, nil, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	expected, _ := json.Marshal([]Ticket{
+		{Ticket: "TICKET-12345", User: "user", Status: "Готово", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Ticket: "TICKET-12346", User: "user", Status: "В работе", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+	})
+	if got := w.String(); got != string(expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, string(expected))
+	}
+}
@@ -0,0 +1,125 @@
+package ticket
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotDedupKey = errors.New("is not a dedup key")
+	ErrConflict    = errors.New("conflicts with an earlier line")
+)
+
+// DedupKey определяет, какие строки считаются одним и тем же тикетом.
+type DedupKey int
+
+const (
+	// DedupByID — один тикет на идентификатор.
+	DedupByID DedupKey = iota
+	// DedupByIDDate — один тикет на идентификатор и момент времени.
+	DedupByIDDate
+	// DedupByLine — одинаковыми считаются только совпадающие строки.
+	DedupByLine
+)
+
+var dedupKeys = map[string]DedupKey{
+	"id":      DedupByID,
+	"id+date": DedupByIDDate,
+	"line":    DedupByLine,
+}
+
+func ParseDedupKey(s string) (DedupKey, error) {
+	key, ok := dedupKeys[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("%v %w", s, ErrNotDedupKey)
+	}
+	return key, nil
+}
+
+func (k DedupKey) key(t Ticket, text string) string {
+	switch k {
+	case DedupByIDDate:
+		return t.Ticket + " " + t.Date.UTC().Format(time.RFC3339Nano)
+	case DedupByLine:
+		return strings.TrimSpace(text)
+	}
+	return t.Ticket
+}
+
+// SourceLine указывает, откуда взят тикет.
+type SourceLine struct {
+	Source string
+	Line   int
+	Text   string
+}
+
+func (l SourceLine) String() string {
+	return fmt.Sprintf("%v:%d %q", l.Source, l.Line, l.Text)
+}
+
+// Conflict — две строки с одним ключом, расходящиеся в остальных полях.
+type Conflict struct {
+	Key    string
+	First  SourceLine
+	Second SourceLine
+}
+
+func (c Conflict) Error() string {
+	return fmt.Sprintf("%v: %v %v %v", c.Key, c.Second, ErrConflict, c.First)
+}
+
+func (c Conflict) Unwrap() error {
+	return ErrConflict
+}
+
+type dedupVariant struct {
+	ticket Ticket
+	line   SourceLine
+}
+
+// Dedup отбрасывает повторы тикетов. Полные повторы (все поля равны) молча пропускаются;
+// строки с тем же ключом, но другими полями, попадают в Conflicts вместе с первой строкой
+// ключа и в результат, чтобы выбор между ними оставался за читателем.
+type Dedup struct {
+	Key        DedupKey
+	Duplicates int
+	Conflicts  []Conflict
+
+	seen    map[string][]dedupVariant
+	tickets []Ticket
+}
+
+func NewDedup(key DedupKey) *Dedup {
+	return &Dedup{Key: key, seen: make(map[string][]dedupVariant)}
+}
+
+func sameTicket(a Ticket, b Ticket) bool {
+	return a.Ticket == b.Ticket && a.User == b.User && a.Status == b.Status && a.Date.Equal(b.Date)
+}
+
+// Add учитывает тикет и возвращает true, если он не повторяет уже добавленный.
+func (d *Dedup) Add(t Ticket, line SourceLine) bool {
+	key := d.Key.key(t, line.Text)
+
+	variants := d.seen[key]
+	for _, variant := range variants {
+		if sameTicket(variant.ticket, t) {
+			d.Duplicates++
+			return false
+		}
+	}
+	if len(variants) > 0 {
+		d.Conflicts = append(d.Conflicts, Conflict{Key: key, First: variants[0].line, Second: line})
+	}
+
+	d.seen[key] = append(variants, dedupVariant{ticket: t, line: line})
+	d.tickets = append(d.tickets, t)
+	return true
+}
+
+// Tickets возвращает тикеты без повторов в порядке первого появления.
+func (d *Dedup) Tickets() []Ticket {
+	return d.tickets
+}
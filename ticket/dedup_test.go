@@ -0,0 +1,97 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedup(t *testing.T) {
+	var lines = []string{
+		"TICKET-1_alice_В работе_2026-01-02",
+		"TICKET-1_alice_В работе_2026-01-02",
+		"TICKET-1_alice_ГОТОВО_2026-01-02",
+		"TICKET-1_alice_Готово_2026-01-03",
+		"TICKET-2_bob_Готово_2026-01-02",
+		"TICKET-2_bob_Готово_2026-01-02 ",
+	}
+
+	var tests = []struct {
+		name       string
+		key        string
+		expected   []string
+		duplicates int
+		conflicts  []Conflict
+	}{
+		{
+			name:       "Case id",
+			key:        "id",
+			expected:   []string{"TICKET-1 В работе", "TICKET-1 Готово", "TICKET-1 Готово", "TICKET-2 Готово"},
+			duplicates: 2,
+			conflicts: []Conflict{
+				{Key: "TICKET-1", First: SourceLine{"log", 1, lines[0]}, Second: SourceLine{"log", 3, lines[2]}},
+				{Key: "TICKET-1", First: SourceLine{"log", 1, lines[0]}, Second: SourceLine{"log", 4, lines[3]}},
+			},
+		},
+		{
+			name:       "Case id and date",
+			key:        "id+date",
+			expected:   []string{"TICKET-1 В работе", "TICKET-1 Готово", "TICKET-1 Готово", "TICKET-2 Готово"},
+			duplicates: 2,
+			conflicts: []Conflict{
+				{Key: "TICKET-1 2026-01-02T00:00:00Z", First: SourceLine{"log", 1, lines[0]}, Second: SourceLine{"log", 3, lines[2]}},
+			},
+		},
+		{
+			name:       "Case line",
+			key:        " LINE ",
+			expected:   []string{"TICKET-1 В работе", "TICKET-1 Готово", "TICKET-1 Готово", "TICKET-2 Готово"},
+			duplicates: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := ParseDedupKey(test.key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+
+			dedup := NewDedup(key)
+			err = ScanTicketLines(context.Background(), strings.NewReader(strings.Join(lines, "\n")), nil, nil, nil, func(line TicketLine) error {
+				dedup.Add(*line.Ticket, SourceLine{Source: "log", Line: line.Number, Text: line.Text})
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+
+			got := make([]string, 0)
+			for _, ticket := range dedup.Tickets() {
+				got = append(got, ticket.Ticket+" "+ticket.Status)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+			if dedup.Duplicates != test.duplicates {
+				t.Errorf("unexpected duplicates: got %v, expected %v\n", dedup.Duplicates, test.duplicates)
+			}
+			if !reflect.DeepEqual(dedup.Conflicts, test.conflicts) {
+				t.Errorf("unexpected conflicts: got %v, expected %v\n", dedup.Conflicts, test.conflicts)
+			}
+			for _, conflict := range dedup.Conflicts {
+				if !errors.Is(conflict, ErrConflict) {
+					t.Errorf("unexpected error: got %v, expected %v\n", conflict, ErrConflict)
+				}
+			}
+		})
+	}
+
+	if _, err := ParseDedupKey("ticket"); !errors.Is(err, ErrNotDedupKey) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, ErrNotDedupKey)
+	}
+}
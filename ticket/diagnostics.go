@@ -0,0 +1,121 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var ErrTooManyRejections = errors.New("too many rejected lines")
+
+type ErrorPolicy int
+
+const (
+	SkipErrors ErrorPolicy = iota
+	FailFast
+	CapErrors
+)
+
+// Rejection описывает строку, которую не удалось превратить в тикет.
+// Cause — одна из ErrParse, ErrNotTicket, ErrNotStatus, ErrLineTooLong или исходная ошибка, если она другая.
+type Rejection struct {
+	Line  int
+	Text  string
+	Cause error
+	Err   error
+}
+
+func (r Rejection) Error() string {
+	return fmt.Sprintf("line %d: %v", r.Line, r.Err)
+}
+
+func (r Rejection) Unwrap() error {
+	return r.Err
+}
+
+func (r Rejection) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Line  int
+		Text  string
+		Cause string
+		Error string
+	}{Line: r.Line, Text: r.Text, Cause: r.Cause.Error(), Error: r.Err.Error()})
+}
+
+func rejectionCause(err error) error {
+	for _, sentinel := range []error{ErrNotTicket, ErrNotStatus, ErrParse, ErrLineTooLong} {
+		if errors.Is(err, sentinel) {
+			return sentinel
+		}
+	}
+	return err
+}
+
+// Diagnostics собирает отклонённые строки. Limit используется только с CapErrors:
+// при превышении лимита разбор прерывается с ErrTooManyRejections.
+type Diagnostics struct {
+	Policy     ErrorPolicy
+	Limit      int
+	Rejections []Rejection
+}
+
+func (d *Diagnostics) reject(line int, text string, err error) error {
+	if d == nil {
+		return nil
+	}
+
+	rejection := Rejection{Line: line, Text: text, Cause: rejectionCause(err), Err: err}
+	d.Rejections = append(d.Rejections, rejection)
+
+	switch d.Policy {
+	case FailFast:
+		return rejection
+	case CapErrors:
+		if len(d.Rejections) > d.Limit {
+			return fmt.Errorf("%d lines %w", len(d.Rejections), ErrTooManyRejections)
+		}
+	}
+	return nil
+}
+
+type Report struct {
+	Tickets    []Ticket
+	Rejections []Rejection
+}
+
+func GetTasksReport(ctx context.Context, r io.Reader, w io.Writer, decoder TicketDecoder, filter Filter, diagnostics *Diagnostics, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if diagnostics == nil {
+		diagnostics = &Diagnostics{}
+	}
+
+	report := Report{Tickets: make([]Ticket, 0)}
+	err := ScanTickets(ctx, r, decoder, diagnostics, func(t *Ticket) error {
+		if filter.Match(t) {
+			report.Tickets = append(report.Tickets, *t)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	report.Rejections = diagnostics.Rejections
+	if report.Rejections == nil {
+		report.Rejections = make([]Rejection, 0)
+	}
+
+	b, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetTasksReport(t *testing.T) {
+	var lines = []string{
+		"TICKET-1_user_Готово_2026-01-02",
+		"invalid-ticket_user_Готово_2026-01-03",
+		"",
+		"TICKET-2_user_invalid-status_2026-01-03",
+		"TICKET-3_user_Готово_invalid-date",
+		"TICKET-4_user_В работе_2026-01-04",
+		"some-text",
+	}
+
+	var tests = []struct {
+		name        string
+		policy      ErrorPolicy
+		limit       int
+		expected    []Ticket
+		expectedErr error
+		rejections  []int
+		causes      []error
+	}{
+		{
+			name:   "Case skip errors",
+			policy: SkipErrors,
+			expected: []Ticket{
+				{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Ticket: "TICKET-4", User: "user", Status: "В работе", Date: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+			},
+			rejections: []int{2, 4, 5, 7},
+			causes:     []error{ErrNotTicket, ErrNotStatus, ErrParse, ErrParse},
+		},
+		{
+			name:        "Case fail fast",
+			policy:      FailFast,
+			expectedErr: ErrNotTicket,
+			rejections:  []int{2},
+			causes:      []error{ErrNotTicket},
+		},
+		{
+			name:        "Case cap errors",
+			policy:      CapErrors,
+			limit:       2,
+			expectedErr: ErrTooManyRejections,
+			rejections:  []int{2, 4, 5},
+			causes:      []error{ErrNotTicket, ErrNotStatus, ErrParse},
+		},
+		{
+			name:   "Case cap not reached",
+			policy: CapErrors,
+			limit:  4,
+			expected: []Ticket{
+				{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Ticket: "TICKET-4", User: "user", Status: "В работе", Date: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+			},
+			rejections: []int{2, 4, 5, 7},
+			causes:     []error{ErrNotTicket, ErrNotStatus, ErrParse, ErrParse},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			diagnostics := &Diagnostics{Policy: test.policy, Limit: test.limit}
+
+			w := bytes.NewBuffer(nil)
+			err := GetTasksReport(context.Background(), strings.NewReader(strings.Join(lines, "\n")), w, DefaultDecoder, nil, diagnostics, 10*time.Millisecond)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+
+			if got := len(diagnostics.Rejections); got != len(test.rejections) {
+				t.Fatalf("unexpected number of rejections: got %v, expected %v\n", got, len(test.rejections))
+			}
+			for i, rejection := range diagnostics.Rejections {
+				if rejection.Line != test.rejections[i] {
+					t.Errorf("unexpected line: got %v, expected %v\n", rejection.Line, test.rejections[i])
+				}
+				if rejection.Text != lines[rejection.Line-1] {
+					t.Errorf("unexpected text: got %v, expected %v\n", rejection.Text, lines[rejection.Line-1])
+				}
+				if rejection.Cause != test.causes[i] || !errors.Is(rejection, test.causes[i]) {
+					t.Errorf("unexpected cause: got %v, expected %v\n", rejection.Cause, test.causes[i])
+				}
+			}
+
+			if test.expectedErr != nil {
+				if w.Len() != 0 {
+					t.Errorf("unexpected output: %v\n", w.String())
+				}
+				return
+			}
+
+			var report struct {
+				Tickets    []Ticket
+				Rejections []struct {
+					Line  int
+					Text  string
+					Cause string
+					Error string
+				}
+			}
+			if err := json.Unmarshal(w.Bytes(), &report); err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+
+			b, _ := json.Marshal(report.Tickets)
+			expected, _ := json.Marshal(test.expected)
+			if string(b) != string(expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", string(b), string(expected))
+			}
+			if len(report.Rejections) != len(test.rejections) || report.Rejections[0].Cause != ErrNotTicket.Error() {
+				t.Errorf("unexpected rejections: got %v\n", report.Rejections)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package ticket
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const icalDateTime = "20060102T150405Z"
+
+// icalStatus сопоставляет статус тикета статусу VTODO из RFC 5545.
+func icalStatus(status string) string {
+	canonical, _ := Statuses.Lookup(status)
+	switch canonical {
+	case Ready:
+		return "COMPLETED"
+	case InProgress:
+		return "IN-PROCESS"
+	case WillNotBeDone:
+		return "CANCELLED"
+	}
+	return "NEEDS-ACTION"
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icalWriter пишет строки содержимого iCalendar: CRLF в конце и перенос строк длиннее 75 байт
+// с пробелом в начале продолжения, не разрывая символы UTF-8.
+type icalWriter struct {
+	w *bufio.Writer
+}
+
+func (iw icalWriter) line(name string, value string) {
+	s := name + ":" + value
+	for first := true; ; first = false {
+		limit := 75
+		if !first {
+			limit = 74
+			iw.w.WriteString(" ")
+		}
+		if len(s) <= limit {
+			iw.w.WriteString(s + "\r\n")
+			return
+		}
+
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		iw.w.WriteString(s[:cut] + "\r\n")
+		s = s[cut:]
+	}
+}
+
+// WriteTicketsICal пишет тикеты календарём iCalendar, по одному VTODO на тикет.
+// Статус переводится в COMPLETED, IN-PROCESS или CANCELLED, пользователь попадает в CATEGORIES.
+// Дата тикета служит и DTSTAMP, поэтому вывод для одних и тех же тикетов не меняется.
+func WriteTicketsICal(w io.Writer, tickets []Ticket) error {
+	bw := bufio.NewWriter(w)
+	iw := icalWriter{w: bw}
+
+	iw.line("BEGIN", "VCALENDAR")
+	iw.line("VERSION", "2.0")
+	iw.line("PRODID", "-//go-2-step-by-step//tickets//RU")
+	for _, t := range tickets {
+		date := t.Date.UTC().Format(icalDateTime)
+		status := icalStatus(t.Status)
+
+		iw.line("BEGIN", "VTODO")
+		iw.line("UID", icalEscaper.Replace(fmt.Sprintf("%v-%v@tickets", t.Ticket, t.Date.UTC().Unix())))
+		iw.line("DTSTAMP", date)
+		iw.line("DTSTART", date)
+		iw.line("SUMMARY", icalEscaper.Replace(t.Ticket))
+		iw.line("DESCRIPTION", icalEscaper.Replace(fmt.Sprintf("%v: %v", t.User, t.Status)))
+		iw.line("CATEGORIES", icalEscaper.Replace(t.User))
+		iw.line("STATUS", status)
+		if status == "COMPLETED" {
+			iw.line("COMPLETED", date)
+		}
+		iw.line("END", "VTODO")
+	}
+	iw.line("END", "VCALENDAR")
+
+	return bw.Flush()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "~", `\~`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+// WriteTicketsMarkdown пишет тикеты чек-листом Markdown, сгруппированным по пользователям
+// в алфавитном порядке. Готовые тикеты отмечены, отменённые ещё и зачёркнуты.
+func WriteTicketsMarkdown(w io.Writer, tickets []Ticket) error {
+	byUser := make(map[string][]Ticket)
+	for _, t := range tickets {
+		byUser[t.User] = append(byUser[t.User], t)
+	}
+	users := make([]string, 0, len(byUser))
+	for user := range byUser {
+		users = append(users, user)
+	}
+	slices.Sort(users)
+
+	bw := bufio.NewWriter(w)
+	for i, user := range users {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "## %s\n\n", markdownEscaper.Replace(user))
+
+		for _, t := range byUser[user] {
+			box, ticket := " ", markdownEscaper.Replace(t.Ticket)
+			switch canonical, _ := Statuses.Lookup(t.Status); canonical {
+			case Ready:
+				box = "x"
+			case WillNotBeDone:
+				box, ticket = "x", "~~"+ticket+"~~"
+			}
+			fmt.Fprintf(bw, "- [%s] %s — %s, %s\n", box, ticket, markdownEscaper.Replace(t.Status), t.Date.Format(DateLayout))
+		}
+	}
+
+	return bw.Flush()
+}
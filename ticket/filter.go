@@ -0,0 +1,448 @@
+package ticket
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrFilterSyntax = errors.New("filter syntax error")
+
+type FilterSyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("%v at position %d: %v", ErrFilterSyntax, e.Pos, e.Msg)
+}
+
+func (e *FilterSyntaxError) Unwrap() error {
+	return ErrFilterSyntax
+}
+
+type Filter func(t *Ticket) bool
+
+func (f Filter) Match(t *Ticket) bool {
+	return f == nil || f(t)
+}
+
+func (f Filter) And(other Filter) Filter {
+	if f == nil {
+		return other
+	}
+	if other == nil {
+		return f
+	}
+	return func(t *Ticket) bool { return f(t) && other(t) }
+}
+
+func TargetFilter(user *string, status *string) Filter {
+	if user == nil && status == nil {
+		return nil
+	}
+	return func(t *Ticket) bool { return t.IsTarget(user, status) }
+}
+
+// CompileFilter компилирует выражение вида:
+//
+//	user in (alice,bob) and status != "Готово" and date >= 2024-03-01
+//
+// Поля: ticket, user, status, date. Операторы: = != < <= > >= in, not in.
+// Условия объединяются через and, or, not и скобки. Пустое выражение пропускает всё.
+// Дни в условиях по date отсчитываются в UTC.
+func CompileFilter(expr string) (Filter, error) {
+	return CompileFilterIn(expr, time.UTC)
+}
+
+// CompileFilterIn работает как CompileFilter, но день 2024-03-01 означает сутки в location.
+func CompileFilterIn(expr string, location *time.Location) (Filter, error) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, location: location}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &FilterSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	return filter, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func isFilterDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`()=!<>,"`, r)
+}
+
+func lexFilter(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{kind: tokenOp, text: "=", pos: pos})
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				i++
+			}
+		case r == '!' || r == '<' || r == '>':
+			op := string(r)
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+			if op == "!" {
+				return nil, &FilterSyntaxError{Pos: pos, Msg: `expected "!="`}
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: pos})
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return nil, &FilterSyntaxError{Pos: pos, Msg: "unterminated string"}
+			}
+			text, err := strconv.Unquote(string(runes[i : j+1]))
+			if err != nil {
+				return nil, &FilterSyntaxError{Pos: pos, Msg: "invalid string"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: pos})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !isFilterDelimiter(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:j]), pos: pos})
+			i = j
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, text: "end of expression", pos: len(runes) + 1}), nil
+}
+
+type filterParser struct {
+	tokens   []token
+	i        int
+	location *time.Location
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *filterParser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokenEOF {
+		p.i++
+	}
+	return tok
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().is("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(t *Ticket) bool { return l(t) || r(t) }
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().is("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(t *Ticket) bool { return l(t) && r(t) }
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (Filter, error) {
+	if p.peek().is("not") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(t *Ticket) bool { return !operand(t) }, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, &FilterSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(`expected ")", got %q`, tok.text)}
+		}
+		return filter, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (Filter, error) {
+	fieldToken := p.next()
+	if fieldToken.kind != tokenWord {
+		return nil, &FilterSyntaxError{Pos: fieldToken.pos, Msg: fmt.Sprintf("expected field, got %q", fieldToken.text)}
+	}
+
+	field := strings.ToLower(fieldToken.text)
+	if !slices.Contains([]string{"ticket", "user", "status", "date"}, field) {
+		return nil, &FilterSyntaxError{Pos: fieldToken.pos, Msg: fmt.Sprintf("unknown field %q", fieldToken.text)}
+	}
+
+	negate := false
+	if p.peek().is("not") {
+		p.next()
+		negate = true
+		if !p.peek().is("in") {
+			tok := p.peek()
+			return nil, &FilterSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(`expected "in", got %q`, tok.text)}
+		}
+	}
+
+	if p.peek().is("in") {
+		p.next()
+		filter, err := p.parseIn(field)
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			return func(t *Ticket) bool { return !filter(t) }, nil
+		}
+		return filter, nil
+	}
+
+	opToken := p.next()
+	if opToken.kind != tokenOp {
+		return nil, &FilterSyntaxError{Pos: opToken.pos, Msg: fmt.Sprintf("expected operator, got %q", opToken.text)}
+	}
+
+	valueToken, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return compileCondition(field, opToken.text, valueToken, p.location)
+}
+
+func (p *filterParser) parseValue() (token, error) {
+	tok := p.next()
+	if tok.kind != tokenWord && tok.kind != tokenString {
+		return tok, &FilterSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected value, got %q", tok.text)}
+	}
+	return tok, nil
+}
+
+func (p *filterParser) parseIn(field string) (Filter, error) {
+	if tok := p.next(); tok.kind != tokenLParen {
+		return nil, &FilterSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(`expected "(", got %q`, tok.text)}
+	}
+
+	conditions := make([]Filter, 0)
+	for {
+		valueToken, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		condition, err := compileCondition(field, "=", valueToken, p.location)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+
+		tok := p.next()
+		if tok.kind == tokenRParen {
+			break
+		}
+		if tok.kind != tokenComma {
+			return nil, &FilterSyntaxError{Pos: tok.pos, Msg: fmt.Sprintf(`expected "," or ")", got %q`, tok.text)}
+		}
+	}
+
+	return func(t *Ticket) bool {
+		for _, condition := range conditions {
+			if condition(t) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func compileCondition(field string, op string, value token, location *time.Location) (Filter, error) {
+	if field == "date" {
+		return compileDateCondition(op, value, location)
+	}
+
+	var get func(t *Ticket) string
+	switch field {
+	case "ticket":
+		get = func(t *Ticket) string { return t.Ticket }
+	case "user":
+		get = func(t *Ticket) string { return t.User }
+	case "status":
+		get = func(t *Ticket) string { return t.Status }
+	}
+
+	v := value.text
+	if field == "ticket" && op != "=" && op != "!=" {
+		return compileTicketCondition(op, value)
+	}
+	if field == "status" {
+		if status, ok := Statuses.Lookup(v); ok {
+			v = string(status)
+		}
+	}
+
+	switch op {
+	case "=":
+		return func(t *Ticket) bool { return get(t) == v }, nil
+	case "!=":
+		return func(t *Ticket) bool { return get(t) != v }, nil
+	case "<":
+		return func(t *Ticket) bool { return get(t) < v }, nil
+	case "<=":
+		return func(t *Ticket) bool { return get(t) <= v }, nil
+	case ">":
+		return func(t *Ticket) bool { return get(t) > v }, nil
+	case ">=":
+		return func(t *Ticket) bool { return get(t) >= v }, nil
+	}
+
+	return nil, &FilterSyntaxError{Pos: value.pos, Msg: fmt.Sprintf("unknown operator %q", op)}
+}
+
+// compileTicketCondition сравнивает идентификаторы по номеру: ticket < TICKET-10 включает TICKET-9.
+func compileTicketCondition(op string, value token) (Filter, error) {
+	compare := func(t *Ticket) int { return compareTicketStrings(t.Ticket, value.text) }
+
+	switch op {
+	case "<":
+		return func(t *Ticket) bool { return compare(t) < 0 }, nil
+	case "<=":
+		return func(t *Ticket) bool { return compare(t) <= 0 }, nil
+	case ">":
+		return func(t *Ticket) bool { return compare(t) > 0 }, nil
+	case ">=":
+		return func(t *Ticket) bool { return compare(t) >= 0 }, nil
+	}
+
+	return nil, &FilterSyntaxError{Pos: value.pos, Msg: fmt.Sprintf("unknown operator %q", op)}
+}
+
+// compileDateCondition сравнивает дату с днём (2006-01-02 в location) или с моментом времени (RFC 3339).
+// Значение рассматривается как полуинтервал [from, to): день целиком или одна наносекунда.
+// Сравниваются моменты времени, поэтому зона самого тикета значения не имеет.
+func compileDateCondition(op string, value token, location *time.Location) (Filter, error) {
+	var from, to time.Time
+	if day, err := time.ParseInLocation(DateLayout, value.text, location); err == nil {
+		from, to = day, day.AddDate(0, 0, 1)
+	} else if instant, err := time.Parse(time.RFC3339, value.text); err == nil {
+		from, to = instant, instant
+	} else {
+		return nil, &FilterSyntaxError{Pos: value.pos, Msg: fmt.Sprintf("invalid date %q", value.text)}
+	}
+
+	if from.Equal(to) {
+		to = from.Add(time.Nanosecond)
+	}
+	in := func(d time.Time) bool { return !d.Before(from) && d.Before(to) }
+
+	switch op {
+	case "=":
+		return func(t *Ticket) bool { return in(t.Date) }, nil
+	case "!=":
+		return func(t *Ticket) bool { return !in(t.Date) }, nil
+	case "<":
+		return func(t *Ticket) bool { return t.Date.Before(from) }, nil
+	case "<=":
+		return func(t *Ticket) bool { return t.Date.Before(to) }, nil
+	case ">":
+		return func(t *Ticket) bool { return !t.Date.Before(to) }, nil
+	case ">=":
+		return func(t *Ticket) bool { return !t.Date.Before(from) }, nil
+	}
+
+	return nil, &FilterSyntaxError{Pos: value.pos, Msg: fmt.Sprintf("unknown operator %q", op)}
+}
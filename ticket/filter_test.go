@@ -0,0 +1,170 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompileFilter(t *testing.T) {
+	var tickets = []*Ticket{
+		{Ticket: "TICKET-1", User: "alice", Status: "Готово", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Ticket: "TICKET-2", User: "alice", Status: "В работе", Date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{Ticket: "TICKET-3", User: "bob", Status: "Не будет сделано", Date: time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{Ticket: "TICKET-4", User: "carol", Status: "В работе", Date: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var tests = []struct {
+		name     string
+		expr     string
+		expected []string
+	}{
+		{name: "Case empty expression", expr: "  ", expected: []string{"TICKET-1", "TICKET-2", "TICKET-3", "TICKET-4"}},
+		{name: "Case equal", expr: "user = alice", expected: []string{"TICKET-1", "TICKET-2"}},
+		{name: "Case double equal", expr: "user == bob", expected: []string{"TICKET-3"}},
+		{name: "Case quoted value", expr: `status = "В работе"`, expected: []string{"TICKET-2", "TICKET-4"}},
+		{name: "Case in", expr: "user in (alice, bob)", expected: []string{"TICKET-1", "TICKET-2", "TICKET-3"}},
+		{name: "Case not in", expr: "user not in (alice,bob)", expected: []string{"TICKET-4"}},
+		{
+			name:     "Case example",
+			expr:     `user in (alice,bob) and status != "Готово" and date >= 2024-03-01`,
+			expected: []string{"TICKET-2"},
+		},
+		{name: "Case or", expr: "user = carol or ticket = TICKET-1", expected: []string{"TICKET-1", "TICKET-4"}},
+		{name: "Case not with parentheses", expr: "not (user = alice or user = bob)", expected: []string{"TICKET-4"}},
+		{name: "Case precedence", expr: "user = bob or user = alice and status = Готово", expected: []string{"TICKET-1", "TICKET-3"}},
+		{name: "Case keywords sensitivity", expr: "USER IN (bob) OR Date = 2024-03-05", expected: []string{"TICKET-3", "TICKET-4"}},
+		{name: "Case date equal", expr: "date = 2024-03-02", expected: []string{"TICKET-2"}},
+		{name: "Case date less", expr: "date < 2024-03-01", expected: []string{"TICKET-3"}},
+		{name: "Case date less or equal", expr: "date <= 2024-03-01", expected: []string{"TICKET-1", "TICKET-3"}},
+		{name: "Case date greater", expr: "date > 2024-03-02", expected: []string{"TICKET-4"}},
+		{name: "Case date instant", expr: "date > 2024-03-01T12:00:00Z", expected: []string{"TICKET-2", "TICKET-4"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := CompileFilter(test.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+
+			got := make([]string, 0)
+			for _, ticket := range tickets {
+				if filter.Match(ticket) {
+					got = append(got, ticket.Ticket)
+				}
+			}
+
+			if strings.Join(got, ",") != strings.Join(test.expected, ",") {
+				t.Errorf("unexpected value for %v: got %v, expected %v\n", test.expr, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestCompileFilterIn(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	// 2024-03-02 01:30 по Москве — это ещё 1 марта в UTC.
+	ticket := &Ticket{Ticket: "TICKET-1", User: "alice", Status: "Готово", Date: time.Date(2024, 3, 1, 22, 30, 0, 0, time.UTC)}
+
+	var tests = []struct {
+		name     string
+		expr     string
+		location *time.Location
+		expected bool
+	}{
+		{name: "Case utc day", expr: "date = 2024-03-01", expected: true},
+		{name: "Case utc next day", expr: "date = 2024-03-02", expected: false},
+		{name: "Case local day", expr: "date = 2024-03-02", location: moscow, expected: true},
+		{name: "Case local previous day", expr: "date <= 2024-03-01", location: moscow, expected: false},
+		{name: "Case instant with offset", expr: "date = 2024-03-02T01:30:00+03:00", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := CompileFilterIn(test.expr, test.location)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+			if got := filter.Match(ticket); got != test.expected {
+				t.Errorf("unexpected value for %v: got %v, expected %v\n", test.expr, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestCompileFilterErrors(t *testing.T) {
+	var tests = []struct {
+		name        string
+		expr        string
+		expectedPos int
+	}{
+		{name: "Case unknown field", expr: "owner = alice", expectedPos: 1},
+		{name: "Case missing operator", expr: "user alice", expectedPos: 6},
+		{name: "Case missing value", expr: "user =", expectedPos: 7},
+		{name: "Case single bang", expr: "user ! alice", expectedPos: 6},
+		{name: "Case unterminated string", expr: `status = "Готово`, expectedPos: 10},
+		{name: "Case invalid date", expr: "date >= 2024-13-01", expectedPos: 9},
+		{name: "Case unclosed parenthesis", expr: "(user = alice", expectedPos: 14},
+		{name: "Case unclosed list", expr: "user in (alice bob)", expectedPos: 16},
+		{name: "Case not without in", expr: "user not = alice", expectedPos: 10},
+		{name: "Case trailing token", expr: "user = alice bob", expectedPos: 14},
+		{name: "Case position in runes", expr: `status = "Готово" and`, expectedPos: 22},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CompileFilter(test.expr)
+
+			if !errors.Is(err, ErrFilterSyntax) {
+				t.Fatalf("unexpected error: got %v, expected %v\n", err, ErrFilterSyntax)
+			}
+
+			var syntaxErr *FilterSyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("unexpected error type: %T\n", err)
+			}
+			if syntaxErr.Pos != test.expectedPos {
+				t.Errorf("unexpected position for %v: got %v, expected %v (%v)\n", test.expr, syntaxErr.Pos, test.expectedPos, err)
+			}
+		})
+	}
+}
+
+func TestGetTasksWithFilter(t *testing.T) {
+	var lines = []string{
+		"TICKET-1_alice_Готово_2024-03-01",
+		"TICKET-2_alice_В работе_2024-03-02",
+		"TICKET-3_bob_В работе_2024-02-28",
+		"TICKET-4_carol_В работе_2024-03-05",
+	}
+
+	filter, err := CompileFilter(`user in (alice,bob) and status != "Готово" and date >= 2024-03-01`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	w := bytes.NewBuffer(nil)
+	err = GetTasks(context.Background(), strings.NewReader(strings.Join(lines, "\n")), w, DefaultDecoder, filter, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	expected, _ := json.Marshal([]Ticket{
+		{Ticket: "TICKET-2", User: "alice", Status: "В работе", Date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+	})
+	if got := w.String(); got != string(expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, string(expected))
+	}
+}
@@ -0,0 +1,274 @@
+package ticket
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultFollowInterval — пауза между проверками файла, когда новых строк нет.
+const DefaultFollowInterval = 250 * time.Millisecond
+
+// FollowOptions настраивает FollowTickets. Нулевое значение читает только строки,
+// дописанные после запуска, и проверяет файл раз в DefaultFollowInterval.
+// Lines делит и ограничивает строки так же, как в ScanTicketsWith.
+type FollowOptions struct {
+	Interval    time.Duration
+	FromStart   bool
+	Diagnostics *Diagnostics
+	Lines       *LineOptions
+}
+
+// TicketEvent — тикет или ошибка, после которой канал FollowChannel закрывается.
+type TicketEvent struct {
+	Ticket *Ticket
+	Err    error
+}
+
+// followedFile — открытый файл журнала и позиция в нём. Незавершённый хвост строки
+// хранится в pending до появления разделителя.
+type followedFile struct {
+	name    string
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	lines   *LineOptions
+	split   bufio.SplitFunc
+	long    *longLine
+	buf     []byte
+	pending []byte
+}
+
+func openFollowed(name string, fromStart bool, lines *LineOptions) (*followedFile, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	f := &followedFile{name: name, file: file, info: info, lines: lines, buf: make([]byte, 4096)}
+	if !fromStart {
+		if f.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	f.split, f.long = lines.split()
+
+	return f, nil
+}
+
+func (f *followedFile) Close() error {
+	return f.file.Close()
+}
+
+// readLines передаёт в fn все завершённые строки, дописанные с прошлого вызова.
+// Пропущенная по LongLineSkip строка приходит в fn как *LineTooLongError.
+func (f *followedFile) readLines(fn func(line string, err error) error) error {
+	for {
+		n, err := f.file.Read(f.buf)
+		f.offset += int64(n)
+		f.pending = append(f.pending, f.buf[:n]...)
+
+		if errSplit := f.splitPending(fn); errSplit != nil {
+			return errSplit
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// splitPending делит pending, как bufio.Scanner делил бы буфер не длиннее maxSize:
+// если разделителя нет и в maxSize байтах, строка слишком длинная.
+func (f *followedFile) splitPending(fn func(line string, err error) error) error {
+	max := f.lines.maxSize()
+	start := 0
+	defer func() {
+		f.pending = f.pending[:copy(f.pending, f.pending[start:])]
+	}()
+
+	for start < len(f.pending) {
+		data := f.pending[start:min(len(f.pending), start+max)]
+		advance, token, err := f.split(data, false)
+		if err != nil {
+			return err
+		}
+		if advance == 0 && token == nil {
+			if len(data) >= max {
+				return bufio.ErrTooLong
+			}
+			return nil
+		}
+		start += advance
+
+		if f.long.skipped {
+			f.long.skipped = false
+			if err := fn("", &LineTooLongError{Limit: max}); err != nil {
+				return err
+			}
+			continue
+		}
+		if token != nil {
+			if err := fn(string(token), nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rotate проверяет, не заменён ли файл новым и не усечён ли он. Заменённый файл дочитывается
+// до конца вызывающим, его последняя строка без перевода строки передаётся в fn, после чего
+// журнал открывается заново с начала; усечённый читается с начала. Пока файла по имени нет, остаёмся на старом.
+func (f *followedFile) rotate(fn func(line string, err error) error) (bool, error) {
+	info, err := os.Stat(f.name)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !os.SameFile(f.info, info) {
+		next, err := openFollowed(f.name, true, f.lines)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return false, nil
+			}
+			return false, err
+		}
+		pending := string(f.pending)
+		discarding := f.long.discarding
+		f.file.Close()
+		*f = *next
+
+		if pending != "" && !discarding {
+			if err := fn(pending, nil); err != nil {
+				return true, err
+			}
+		}
+		return true, nil
+	}
+
+	if info.Size() < f.offset {
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		f.offset = 0
+		f.pending = f.pending[:0]
+		f.long.discarding = false
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// FollowTickets следит за растущим журналом name как tail -F и передаёт в fn каждый
+// подходящий под filter тикет из дописанных строк. Замену файла при ротации и усечение
+// FollowTickets переживает. Возвращает ctx.Err() после отмены или первую ошибку чтения либо fn.
+func FollowTickets(ctx context.Context, name string, decoder TicketDecoder, filter Filter, options *FollowOptions, fn func(t *Ticket) error) error {
+	if decoder == nil {
+		decoder = DefaultDecoder
+	}
+	if options == nil {
+		options = &FollowOptions{}
+	}
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DefaultFollowInterval
+	}
+
+	f, err := openFollowed(name, options.FromStart, options.Lines)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	number := 0
+	handle := func(line string, err error) error {
+		number++
+		if err != nil {
+			return options.Diagnostics.reject(number, "", err)
+		}
+		text := strings.TrimSpace(line)
+		if text == "" {
+			return nil
+		}
+
+		theTicket, err := decoder.Decode(text)
+		if err != nil {
+			return options.Diagnostics.reject(number, line, err)
+		}
+		if filter.Match(theTicket) {
+			return fn(theTicket)
+		}
+		return nil
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := f.readLines(handle); err != nil {
+			return err
+		}
+
+		rotated, err := f.rotate(handle)
+		if err != nil {
+			return err
+		}
+		if rotated {
+			number = 0
+			timer.Reset(0)
+			continue
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+// FollowChannel работает как FollowTickets, но отдаёт тикеты в канал. Канал закрывается
+// после отмены ctx или после события с ошибкой.
+func FollowChannel(ctx context.Context, name string, decoder TicketDecoder, filter Filter, options *FollowOptions) <-chan TicketEvent {
+	channel := make(chan TicketEvent)
+
+	go func() {
+		defer close(channel)
+
+		err := FollowTickets(ctx, name, decoder, filter, options, func(t *Ticket) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case channel <- TicketEvent{Ticket: t}:
+				return nil
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case channel <- TicketEvent{Err: err}:
+			}
+		}
+	}()
+
+	return channel
+}
@@ -0,0 +1,282 @@
+package ticket
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func appendFile(t *testing.T, name string, s string) {
+	t.Helper()
+
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(s); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+}
+
+func receiveTickets(t *testing.T, events <-chan TicketEvent, count int) []string {
+	t.Helper()
+
+	got := make([]string, 0, count)
+	for len(got) < count {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("unexpected closed channel after %v\n", got)
+			}
+			if event.Err != nil {
+				t.Fatalf("unexpected error: %v\n", event.Err)
+			}
+			got = append(got, event.Ticket.Ticket)
+		case <-time.After(time.Second):
+			t.Fatalf("unexpected timeout after %v\n", got)
+		}
+	}
+	return got
+}
+
+func TestFollowTickets(t *testing.T) {
+	var tests = []struct {
+		name     string
+		initial  string
+		steps    []func(t *testing.T, name string)
+		options  *FollowOptions
+		expected []string
+	}{
+		{
+			name:    "Case appended lines",
+			initial: "TICKET-1_alice_Готово_2026-01-01\n",
+			steps: []func(t *testing.T, name string){
+				func(t *testing.T, name string) {
+					appendFile(t, name, "TICKET-2_bob_Готово_2026-01-02\nTICKET-3_bob_Гот")
+				},
+				func(t *testing.T, name string) { appendFile(t, name, "ово_2026-01-03\nsome-text\n") },
+			},
+			expected: []string{"TICKET-2", "TICKET-3"},
+		},
+		{
+			name:    "Case from start",
+			initial: "TICKET-1_alice_Готово_2026-01-01\n",
+			options: &FollowOptions{FromStart: true},
+			steps: []func(t *testing.T, name string){
+				func(t *testing.T, name string) { appendFile(t, name, "TICKET-2_bob_Готово_2026-01-02\n") },
+			},
+			expected: []string{"TICKET-1", "TICKET-2"},
+		},
+		{
+			name:    "Case rotation",
+			initial: "TICKET-1_alice_Готово_2026-01-01\n",
+			steps: []func(t *testing.T, name string){
+				func(t *testing.T, name string) { appendFile(t, name, "TICKET-2_bob_Готово_2026-01-02") },
+				func(t *testing.T, name string) {
+					if err := os.Rename(name, name+".1"); err != nil {
+						t.Fatalf("unexpected error: %v\n", err)
+					}
+					appendFile(t, name, "TICKET-3_bob_Готово_2026-01-03\n")
+				},
+			},
+			expected: []string{"TICKET-2", "TICKET-3"},
+		},
+		{
+			name:    "Case truncation",
+			initial: "TICKET-1_alice_Готово_2026-01-01\nTICKET-2_alice_Готово_2026-01-01\n",
+			steps: []func(t *testing.T, name string){
+				func(t *testing.T, name string) {
+					if err := os.Truncate(name, 0); err != nil {
+						t.Fatalf("unexpected error: %v\n", err)
+					}
+				},
+				func(t *testing.T, name string) { appendFile(t, name, "TICKET-3_bob_Готово_2026-01-03\n") },
+			},
+			expected: []string{"TICKET-3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			name := filepath.Join(t.TempDir(), "tickets.log")
+			appendFile(t, name, test.initial)
+
+			options := test.options
+			if options == nil {
+				options = &FollowOptions{}
+			}
+			options.Interval = time.Millisecond
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := FollowChannel(ctx, name, DefaultDecoder, nil, options)
+			for _, step := range test.steps {
+				// Даём FollowTickets заметить предыдущее изменение до следующего.
+				time.Sleep(20 * time.Millisecond)
+				step(t, name)
+			}
+
+			got := receiveTickets(t, events, len(test.expected))
+			for i := range test.expected {
+				if got[i] != test.expected[i] {
+					t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+					break
+				}
+			}
+
+			cancel()
+			select {
+			case event, ok := <-events:
+				if ok {
+					t.Errorf("unexpected event after cancel: %v\n", event)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("unexpected open channel after cancel\n")
+			}
+		})
+	}
+}
+
+func TestFollowTicketsStop(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "tickets.log")
+	appendFile(t, name, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := FollowTickets(ctx, name, nil, nil, &FollowOptions{Interval: time.Hour}, func(t *Ticket) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, context.DeadlineExceeded)
+	}
+	if duration := time.Since(start); duration > time.Second {
+		t.Errorf("unexpected execution time: got %v, expected cancellation well before interval\n", duration)
+	}
+
+	err = FollowTickets(context.Background(), filepath.Join(t.TempDir(), "missing.log"), nil, nil, nil, nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, os.ErrNotExist)
+	}
+
+	appendFile(t, name, "some-text\n")
+	diagnostics := &Diagnostics{Policy: FailFast}
+	err = FollowTickets(context.Background(), name, nil, nil, &FollowOptions{FromStart: true, Diagnostics: diagnostics}, nil)
+	var rejection Rejection
+	if !errors.As(err, &rejection) || rejection.Line != 1 {
+		t.Errorf("unexpected error: got %v, expected rejection of line 1\n", err)
+	}
+}
+
+func TestFollowTicketsLongLines(t *testing.T) {
+	long := "TICKET-9_" + strings.Repeat("x", 64) + "_Готово_2026-01-09\n"
+
+	var tests = []struct {
+		name             string
+		lines            *LineOptions
+		expected         []string
+		expectedRejected int
+		expectedErr      error
+	}{
+		{
+			name:        "Case error",
+			lines:       &LineOptions{MaxSize: 48},
+			expected:    []string{"TICKET-1"},
+			expectedErr: bufio.ErrTooLong,
+		},
+		{
+			name:             "Case skip",
+			lines:            &LineOptions{MaxSize: 48, LongLines: LongLineSkip},
+			expected:         []string{"TICKET-1", "TICKET-2"},
+			expectedRejected: 1,
+			expectedErr:      context.DeadlineExceeded,
+		},
+		{
+			name:        "Case default limit",
+			expected:    []string{"TICKET-1", "TICKET-9", "TICKET-2"},
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			name := filepath.Join(t.TempDir(), "tickets.log")
+			appendFile(t, name, "TICKET-1_alice_Готово_2026-01-01\n"+long+"TICKET-2_bob_Готово_2026-01-02\n")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			diagnostics := &Diagnostics{}
+			options := &FollowOptions{Interval: time.Millisecond, FromStart: true, Diagnostics: diagnostics, Lines: test.lines}
+			got := make([]string, 0)
+			err := FollowTickets(ctx, name, nil, nil, options, func(t *Ticket) error {
+				got = append(got, t.Ticket)
+				return nil
+			})
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+			if len(diagnostics.Rejections) != test.expectedRejected {
+				t.Errorf("unexpected rejections: got %v, expected %v\n", diagnostics.Rejections, test.expectedRejected)
+			}
+		})
+	}
+}
+
+func TestSplitPendingByteByByte(t *testing.T) {
+	var tests = []struct {
+		name     string
+		lines    *LineOptions
+		expected []Line
+	}{
+		{
+			name:     "Case truncate",
+			lines:    &LineOptions{MaxSize: 8, Split: ScanCRLF, LongLines: LongLineTruncate},
+			expected: []Line{{"xxxxxxxx", nil}, {"short", nil}},
+		},
+		{
+			name:     "Case skip",
+			lines:    &LineOptions{MaxSize: 8, Split: ScanCRLF, LongLines: LongLineSkip},
+			expected: []Line{{"", &LineTooLongError{Limit: 8}}, {"short", nil}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := &followedFile{lines: test.lines}
+			f.split, f.long = test.lines.split()
+
+			// Дописываем по байту, как если бы каждый опрос находил в файле один новый байт.
+			got := make([]Line, 0)
+			for _, b := range []byte("xxxxxxxxxx\r\nshort\r\n") {
+				f.pending = append(f.pending, b)
+				err := f.splitPending(func(line string, err error) error {
+					got = append(got, Line{line, err})
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v\n", err)
+				}
+			}
+
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %q, expected %q\n", got, test.expected)
+			}
+		})
+	}
+}
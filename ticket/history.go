@@ -0,0 +1,105 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// History хранит все строки одного тикета в порядке дат.
+type History struct {
+	Ticket   string
+	Timeline []Ticket
+}
+
+func (h *History) add(t Ticket) {
+	i := sort.Search(len(h.Timeline), func(i int) bool { return h.Timeline[i].Date.After(t.Date) })
+	h.Timeline = append(h.Timeline, Ticket{})
+	copy(h.Timeline[i+1:], h.Timeline[i:])
+	h.Timeline[i] = t
+}
+
+func (h *History) Latest() *Ticket {
+	if len(h.Timeline) == 0 {
+		return nil
+	}
+	latest := h.Timeline[len(h.Timeline)-1]
+	return &latest
+}
+
+// Until возвращает историю, обрезанную по дате включительно.
+func (h *History) Until(date time.Time) *History {
+	i := sort.Search(len(h.Timeline), func(i int) bool { return h.Timeline[i].Date.After(date) })
+	return &History{Ticket: h.Ticket, Timeline: h.Timeline[:i]}
+}
+
+// At возвращает состояние тикета на дату или nil, если тикета ещё не было.
+func (h *History) At(date time.Time) *Ticket {
+	return h.Until(date).Latest()
+}
+
+func CollectHistory(ctx context.Context, r io.Reader, decoder TicketDecoder) ([]*History, error) {
+	histories := make([]*History, 0)
+	byTicket := make(map[string]*History)
+
+	err := ScanTickets(ctx, r, decoder, nil, func(t *Ticket) error {
+		history, found := byTicket[t.Ticket]
+		if !found {
+			history = &History{Ticket: t.Ticket}
+			byTicket[t.Ticket] = history
+			histories = append(histories, history)
+		}
+		history.add(*t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+type TicketHistory struct {
+	Ticket   string
+	State    Ticket
+	Timeline []Ticket
+}
+
+// SelectHistory фильтрует истории по последнему состоянию тикета или, если задан asOf, по состоянию на эту дату.
+func SelectHistory(histories []*History, filter Filter, asOf *time.Time) []TicketHistory {
+	selected := make([]TicketHistory, 0)
+	for _, history := range histories {
+		if asOf != nil {
+			history = history.Until(*asOf)
+		}
+
+		state := history.Latest()
+		if state == nil || !filter.Match(state) {
+			continue
+		}
+
+		selected = append(selected, TicketHistory{Ticket: history.Ticket, State: *state, Timeline: history.Timeline})
+	}
+	return selected
+}
+
+func GetHistory(ctx context.Context, r io.Reader, w io.Writer, decoder TicketDecoder, filter Filter, asOf *time.Time, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	histories, err := CollectHistory(ctx, r, decoder)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(SelectHistory(histories, filter, asOf))
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	return nil
+}
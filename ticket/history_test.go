@@ -0,0 +1,164 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+func TestHistory(t *testing.T) {
+	var (
+		day1 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		day2 = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		day3 = time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+
+		created  = Ticket{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1}
+		finished = Ticket{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day3}
+	)
+
+	history := &History{Ticket: "TICKET-1"}
+	history.add(finished)
+	history.add(created)
+
+	if !reflect.DeepEqual(history.Timeline, []Ticket{created, finished}) {
+		t.Errorf("unexpected timeline: got %v\n", history.Timeline)
+	}
+
+	var tests = []struct {
+		name     string
+		date     time.Time
+		expected *Ticket
+	}{
+		{name: "Case before creation", date: day1.Add(-time.Hour)},
+		{name: "Case creation day", date: day1, expected: &created},
+		{name: "Case between", date: day2, expected: &created},
+		{name: "Case finish day", date: day3, expected: &finished},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := history.At(test.date); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	var (
+		day1 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		day2 = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		day3 = time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+	)
+
+	var lines = []string{
+		"TICKET-1_user_Готово_2026-01-03",
+		"TICKET-1_user_В работе_2026-01-01",
+		"TICKET-2_another_В работе_2026-01-02",
+		"invalid-ticket_user_Готово_2026-01-03",
+	}
+
+	ready, _ := CompileFilter(`status = "Готово"`)
+	inProgress, _ := CompileFilter(`status = "В работе"`)
+
+	var tests = []struct {
+		name        string
+		reader      io.Reader
+		filter      Filter
+		asOf        *time.Time
+		expected    []TicketHistory
+		expectedErr error
+	}{
+		{
+			name:   "Case latest ready",
+			reader: strings.NewReader(strings.Join(lines, "\n")),
+			filter: ready,
+			expected: []TicketHistory{
+				{
+					Ticket: "TICKET-1",
+					State:  Ticket{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day3},
+					Timeline: []Ticket{
+						{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1},
+						{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day3},
+					},
+				},
+			},
+		},
+		{
+			name:   "Case latest in progress",
+			reader: strings.NewReader(strings.Join(lines, "\n")),
+			filter: inProgress,
+			expected: []TicketHistory{
+				{
+					Ticket:   "TICKET-2",
+					State:    Ticket{Ticket: "TICKET-2", User: "another", Status: "В работе", Date: day2},
+					Timeline: []Ticket{{Ticket: "TICKET-2", User: "another", Status: "В работе", Date: day2}},
+				},
+			},
+		},
+		{
+			name:   "Case in progress as of date",
+			reader: strings.NewReader(strings.Join(lines, "\n")),
+			filter: inProgress,
+			asOf:   &day2,
+			expected: []TicketHistory{
+				{
+					Ticket:   "TICKET-1",
+					State:    Ticket{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1},
+					Timeline: []Ticket{{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1}},
+				},
+				{
+					Ticket:   "TICKET-2",
+					State:    Ticket{Ticket: "TICKET-2", User: "another", Status: "В работе", Date: day2},
+					Timeline: []Ticket{{Ticket: "TICKET-2", User: "another", Status: "В работе", Date: day2}},
+				},
+			},
+		},
+		{
+			name:     "Case before any ticket",
+			reader:   strings.NewReader(strings.Join(lines, "\n")),
+			asOf:     &time.Time{},
+			expected: []TicketHistory{},
+		},
+		{
+			name: "Case read error",
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				n = copy(p, []byte("TICKET-1_user_Готово_2026-01-03\n"))
+				return n, testutils.FakeReadError
+			}),
+			expectedErr: testutils.FakeReadError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := bytes.NewBuffer(nil)
+			err := GetHistory(context.Background(), test.reader, w, DefaultDecoder, test.filter, test.asOf, 10*time.Millisecond)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+
+			var expected string
+			if test.expected != nil {
+				b, _ := json.Marshal(test.expected)
+				expected = string(b)
+			}
+			if got := w.String(); got != expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package ticket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+// indexVersion меняется вместе с форматом файла индекса; индекс другой версии перестраивается.
+const indexVersion = 2
+
+// indexHeadSize — сколько первых байт журнала хранится в индексе, чтобы заметить замену файла.
+const indexHeadSize = 256
+
+var ErrIndexVersion = errors.New("is not a supported index version")
+
+// IndexEntry указывает на строку журнала с тикетом: Offset и Length в байтах без перевода строки.
+type IndexEntry struct {
+	Offset int64
+	Length int
+	User   string
+	Status string
+	Date   time.Time
+}
+
+// Index хранит смещения тикетов журнала. Entries упорядочены по Offset; Size — сколько байт
+// журнала уже разобрано, незавершённая последняя строка в индекс не попадает.
+// Decoder описывает декодер и реестр статусов, которыми разобраны записи.
+// Выборки по пользователю, статусу и дате строятся при загрузке и не сохраняются.
+type Index struct {
+	Version int
+	Decoder string
+	Size    int64
+	Head    []byte
+	Entries []IndexEntry
+
+	byUser   map[string][]int
+	byStatus map[string][]int
+	byDate   []int
+}
+
+func NewIndex() *Index {
+	ix := &Index{Version: indexVersion}
+	ix.reindex()
+	return ix
+}
+
+func (ix *Index) reindex() {
+	ix.byUser = make(map[string][]int)
+	ix.byStatus = make(map[string][]int)
+	ix.byDate = make([]int, 0, len(ix.Entries))
+	for i := range ix.Entries {
+		ix.add(i)
+	}
+	ix.sortByDate()
+}
+
+func (ix *Index) add(i int) {
+	entry := ix.Entries[i]
+	ix.byUser[entry.User] = append(ix.byUser[entry.User], i)
+	ix.byStatus[entry.Status] = append(ix.byStatus[entry.Status], i)
+	ix.byDate = append(ix.byDate, i)
+}
+
+func (ix *Index) sortByDate() {
+	sort.SliceStable(ix.byDate, func(a int, b int) bool {
+		return ix.Entries[ix.byDate[a]].Date.Before(ix.Entries[ix.byDate[b]].Date)
+	})
+}
+
+func LoadIndex(name string) (*Index, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ix := &Index{}
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(ix); err != nil {
+		return nil, err
+	}
+	if ix.Version != indexVersion {
+		return nil, fmt.Errorf("%d %w", ix.Version, ErrIndexVersion)
+	}
+
+	ix.reindex()
+	return ix, nil
+}
+
+// Save записывает индекс во временный файл рядом и переименовывает его,
+// чтобы оборванная запись не портила прежний индекс.
+func (ix *Index) Save(name string) error {
+	file, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	w := bufio.NewWriter(file)
+	if err := gob.NewEncoder(w).Encode(ix); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), name)
+}
+
+// Update дочитывает журнал с позиции Size и добавляет новые тикеты. Если журнал стал короче,
+// начинается иначе или разбирается другим декодером, чем при прошлом построении,
+// индекс строится заново.
+// Возвращает true, если индекс изменился.
+func (ix *Index) Update(ctx context.Context, logName string, decoder TicketDecoder) (bool, error) {
+	if decoder == nil {
+		decoder = DefaultDecoder
+	}
+
+	file, err := os.Open(logName)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	head := make([]byte, min(int64(indexHeadSize), info.Size()))
+	if _, err := io.ReadFull(file, head); err != nil {
+		return false, err
+	}
+
+	identity := decoderIdentity(decoder)
+
+	changed := false
+	if info.Size() < ix.Size || !bytes.HasPrefix(head, ix.Head) || ix.Version != indexVersion || ix.Decoder != identity {
+		*ix = Index{Version: indexVersion, Decoder: identity}
+		ix.reindex()
+		changed = true
+	}
+	if len(head) > len(ix.Head) {
+		ix.Head = head
+		changed = true
+	}
+
+	if info.Size() == ix.Size {
+		return changed, nil
+	}
+
+	if _, err := file.Seek(ix.Size, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	offset := ix.Size
+	added := len(ix.Entries)
+	reader := bufio.NewReader(file)
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+
+		text := bytes.TrimRight(line, "\r\n")
+		if theTicket, err := decoder.Decode(string(bytes.TrimSpace(text))); err == nil {
+			ix.Entries = append(ix.Entries, IndexEntry{
+				Offset: offset,
+				Length: len(text),
+				User:   theTicket.User,
+				Status: theTicket.Status,
+				Date:   theTicket.Date,
+			})
+			ix.add(len(ix.Entries) - 1)
+		}
+
+		offset += int64(len(line))
+	}
+
+	ix.Size = offset
+	if len(ix.Entries) > added {
+		ix.sortByDate()
+	}
+
+	return true, nil
+}
+
+// decoderIdentity описывает всё, от чего зависят записи индекса: формат,
+// разделитель, раскладки дат, часовой пояс и псевдонимы статусов.
+func decoderIdentity(decoder TicketDecoder) string {
+	var format string
+	switch d := decoder.(type) {
+	case UnderscoreDecoder:
+		format = fmt.Sprintf("underscore %q %v", d.Sep, d.Dates.identity())
+	case CSVDecoder:
+		format = fmt.Sprintf("csv %q %v", d.Comma, d.Dates.identity())
+	case JSONLinesDecoder:
+		format = fmt.Sprintf("jsonl %v", d.Dates.identity())
+	case KeyValueDecoder:
+		format = fmt.Sprintf("kv %v", d.Dates.identity())
+	default:
+		format = fmt.Sprintf("%T", decoder)
+	}
+	return format + "; " + Statuses.identity()
+}
+
+// OpenIndex загружает индекс журнала logName из indexName, дополняет его новыми строками
+// и сохраняет, если он изменился. Отсутствующий или несовместимый индекс строится заново.
+func OpenIndex(ctx context.Context, logName string, indexName string, decoder TicketDecoder) (*Index, error) {
+	ix, err := LoadIndex(indexName)
+	if err != nil {
+		ix = NewIndex()
+	}
+
+	changed, err := ix.Update(ctx, logName, decoder)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := ix.Save(indexName); err != nil {
+			return nil, err
+		}
+	}
+
+	return ix, nil
+}
+
+// IndexQuery выбирает тикеты индекса. Пустые поля не ограничивают выборку;
+// Since включается, Until нет.
+type IndexQuery struct {
+	User   string
+	Status string
+	Since  time.Time
+	Until  time.Time
+}
+
+func (q IndexQuery) matchDate(date time.Time) bool {
+	if !q.Since.IsZero() && date.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !date.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Lookup возвращает подходящие записи в порядке следования в журнале.
+func (ix *Index) Lookup(query IndexQuery) []IndexEntry {
+	var candidates []int
+	switch {
+	case query.User != "" && query.Status != "":
+		candidates = intersectSorted(ix.byUser[query.User], ix.byStatus[normalizedStatus(query.Status)])
+	case query.User != "":
+		candidates = ix.byUser[query.User]
+	case query.Status != "":
+		candidates = ix.byStatus[normalizedStatus(query.Status)]
+	default:
+		// Только даты: двоичный поиск по byDate, затем возвращаем порядок журнала.
+		from, to := 0, len(ix.byDate)
+		if !query.Since.IsZero() {
+			from = sort.Search(len(ix.byDate), func(i int) bool { return !ix.Entries[ix.byDate[i]].Date.Before(query.Since) })
+		}
+		if !query.Until.IsZero() {
+			to = sort.Search(len(ix.byDate), func(i int) bool { return !ix.Entries[ix.byDate[i]].Date.Before(query.Until) })
+		}
+		candidates = slices.Clone(ix.byDate[from:max(from, to)])
+		slices.Sort(candidates)
+	}
+
+	entries := make([]IndexEntry, 0, len(candidates))
+	for _, i := range candidates {
+		if query.matchDate(ix.Entries[i].Date) {
+			entries = append(entries, ix.Entries[i])
+		}
+	}
+	return entries
+}
+
+func normalizedStatus(s string) string {
+	if status, ok := Statuses.Lookup(s); ok {
+		return string(status)
+	}
+	return s
+}
+
+func intersectSorted(a []int, b []int) []int {
+	result := make([]int, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// ReadIndexed читает из журнала только строки записей entries, переходя к каждой через Seek,
+// и разбирает их decoder'ом.
+func ReadIndexed(ctx context.Context, logName string, entries []IndexEntry, decoder TicketDecoder) ([]Ticket, error) {
+	if decoder == nil {
+		decoder = DefaultDecoder
+	}
+
+	file, err := os.Open(logName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tickets := make([]Ticket, 0, len(entries))
+	buf := make([]byte, 0)
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if _, err := file.Seek(entry.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		buf = slices.Grow(buf[:0], entry.Length)[:entry.Length]
+		if _, err := io.ReadFull(file, buf); err != nil {
+			return nil, err
+		}
+
+		theTicket, err := decoder.Decode(string(bytes.TrimSpace(buf)))
+		if err != nil {
+			return nil, fmt.Errorf("offset %d: %w", entry.Offset, err)
+		}
+		tickets = append(tickets, *theTicket)
+	}
+
+	return tickets, nil
+}
+
+// QueryIndex обновляет индекс журнала и возвращает тикеты, подходящие под query и filter.
+// filter проверяется уже на прочитанных тикетах, поэтому может содержать условия, которых нет в индексе.
+func QueryIndex(ctx context.Context, logName string, indexName string, decoder TicketDecoder, query IndexQuery, filter Filter) ([]Ticket, error) {
+	ix, err := OpenIndex(ctx, logName, indexName, decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := ReadIndexed(ctx, logName, ix.Lookup(query), decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(tickets, func(t Ticket) bool { return !filter.Match(&t) }), nil
+}
@@ -0,0 +1,178 @@
+package ticket
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ticketIDs(tickets []Ticket) []string {
+	ids := make([]string, 0, len(tickets))
+	for _, t := range tickets {
+		ids = append(ids, t.Ticket)
+	}
+	return ids
+}
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	logName := filepath.Join(dir, "tickets.log")
+	indexName := filepath.Join(dir, "tickets.idx")
+
+	appendFile(t, logName, "TICKET-1_alice_Готово_2026-01-03\n"+
+		"some-text\n"+
+		"TICKET-2_bob_В работе_2026-01-01\r\n"+
+		"TICKET-3_alice_В работе_2026-01-02\n"+
+		"TICKET-4_bob_Гот")
+
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	var tests = []struct {
+		name     string
+		query    IndexQuery
+		filter   string
+		expected []string
+	}{
+		{name: "Case all", expected: []string{"TICKET-1", "TICKET-2", "TICKET-3"}},
+		{name: "Case user", query: IndexQuery{User: "alice"}, expected: []string{"TICKET-1", "TICKET-3"}},
+		{name: "Case status alias", query: IndexQuery{Status: "in progress"}, expected: []string{"TICKET-2", "TICKET-3"}},
+		{name: "Case user and status", query: IndexQuery{User: "alice", Status: "В работе"}, expected: []string{"TICKET-3"}},
+		{name: "Case dates", query: IndexQuery{Since: day(2), Until: day(4)}, expected: []string{"TICKET-1", "TICKET-3"}},
+		{name: "Case user and dates", query: IndexQuery{User: "bob", Since: day(2)}, expected: []string{}},
+		{name: "Case filter", query: IndexQuery{User: "alice"}, filter: "ticket != TICKET-1", expected: []string{"TICKET-3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := CompileFilter(test.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+
+			got, err := QueryIndex(context.Background(), logName, indexName, nil, test.query, filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+			if ids := ticketIDs(got); !reflect.DeepEqual(ids, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", ids, test.expected)
+			}
+		})
+	}
+
+	ix, err := LoadIndex(indexName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	size := ix.Size
+
+	t.Run("Case log grows", func(t *testing.T) {
+		appendFile(t, logName, "ово_2026-01-04\nTICKET-5_carol_Готово_2026-01-05\n")
+
+		changed, err := ix.Update(context.Background(), logName, nil)
+		if err != nil || !changed {
+			t.Fatalf("unexpected result: %v %v\n", changed, err)
+		}
+		if ix.Entries[3].Offset != size {
+			t.Errorf("unexpected offset: got %v, expected %v\n", ix.Entries[3].Offset, size)
+		}
+		if ids := ticketIDs(mustRead(t, logName, ix.Lookup(IndexQuery{Since: day(4)}))); !reflect.DeepEqual(ids, []string{"TICKET-4", "TICKET-5"}) {
+			t.Errorf("unexpected value: got %v\n", ids)
+		}
+
+		changed, err = ix.Update(context.Background(), logName, nil)
+		if err != nil || changed {
+			t.Errorf("unexpected result for unchanged log: %v %v\n", changed, err)
+		}
+	})
+
+	t.Run("Case log replaced", func(t *testing.T) {
+		_ = os.WriteFile(logName, []byte("TICKET-9_dave_Готово_2026-02-01\n"+
+			"TICKET-8_dave_Готово_2026-02-01\n"+
+			"TICKET-7_dave_Готово_2026-02-01\n"+
+			"TICKET-6_dave_Готово_2026-02-01\n"), 0666)
+
+		got, err := QueryIndex(context.Background(), logName, indexName, nil, IndexQuery{}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v\n", err)
+		}
+		if ids := ticketIDs(got); !reflect.DeepEqual(ids, []string{"TICKET-9", "TICKET-8", "TICKET-7", "TICKET-6"}) {
+			t.Errorf("unexpected value: got %v\n", ids)
+		}
+	})
+
+	t.Run("Case corrupted index", func(t *testing.T) {
+		_ = os.WriteFile(indexName, []byte("garbage"), 0666)
+
+		got, err := QueryIndex(context.Background(), logName, indexName, nil, IndexQuery{User: "dave"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v\n", err)
+		}
+		if len(got) != 4 {
+			t.Errorf("unexpected number of tickets: got %v, expected %v\n", len(got), 4)
+		}
+	})
+}
+
+func mustRead(t *testing.T, logName string, entries []IndexEntry) []Ticket {
+	t.Helper()
+
+	tickets, err := ReadIndexed(context.Background(), logName, entries, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	return tickets
+}
+
+func TestIndexDecoderChanged(t *testing.T) {
+	dir := t.TempDir()
+	logName := filepath.Join(dir, "tickets.log")
+	indexName := filepath.Join(dir, "tickets.idx")
+
+	appendFile(t, logName, "TICKET-1,alice,Готово,2026-01-03\n"+
+		"TICKET-2_bob_Готово_2026-01-01\n")
+
+	var tests = []struct {
+		name     string
+		decoder  TicketDecoder
+		expected []string
+	}{
+		{name: "Case underscore", decoder: nil, expected: []string{"TICKET-2"}},
+		{name: "Case csv", decoder: CSVDecoder{Comma: ','}, expected: []string{"TICKET-1"}},
+		{name: "Case csv with other location", decoder: CSVDecoder{Comma: ',', Dates: DateParser{Location: time.FixedZone("MSK", 3*60*60)}}, expected: []string{"TICKET-1"}},
+		{name: "Case underscore again", decoder: UnderscoreDecoder{Sep: "_"}, expected: []string{"TICKET-2"}},
+	}
+
+	for _, test := range tests {
+		got, err := QueryIndex(context.Background(), logName, indexName, test.decoder, IndexQuery{}, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v\n", test.name, err)
+		}
+		if ids := ticketIDs(got); !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("%s: unexpected value: got %v, expected %v\n", test.name, ids, test.expected)
+		}
+
+		ix, err := LoadIndex(indexName)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v\n", test.name, err)
+		}
+		decoder := test.decoder
+		if decoder == nil {
+			decoder = DefaultDecoder
+		}
+		if ix.Decoder != decoderIdentity(decoder) {
+			t.Errorf("%s: unexpected decoder: got %q, expected %q\n", test.name, ix.Decoder, decoderIdentity(decoder))
+		}
+	}
+
+	t.Run("Case status registry changed", func(t *testing.T) {
+		registry := NewStatusRegistry()
+		before := registry.identity()
+		registry.Register("Готово", "Done")
+		if registry.identity() == before {
+			t.Errorf("unexpected identity: got %q after Register\n", before)
+		}
+	})
+}
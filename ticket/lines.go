@@ -0,0 +1,207 @@
+package ticket
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrLineTooLong  = errors.New("line is too long")
+	ErrNotLongLines = errors.New("is not a long line policy")
+	ErrNotSplit     = errors.New("is not a split function")
+)
+
+// DefaultMaxLineSize — предел bufio.Scanner по умолчанию.
+const DefaultMaxLineSize = bufio.MaxScanTokenSize
+
+// LongLinePolicy определяет, что делать со строкой длиннее LineOptions.MaxSize.
+type LongLinePolicy int
+
+const (
+	// LongLineError прерывает чтение с bufio.ErrTooLong.
+	LongLineError LongLinePolicy = iota
+	// LongLineTruncate отдаёт первые MaxSize байт строки, остаток отбрасывается.
+	LongLineTruncate
+	// LongLineSkip отбрасывает строку и отдаёт вместо неё Line с *LineTooLongError.
+	LongLineSkip
+)
+
+var longLinePolicies = map[string]LongLinePolicy{
+	"error":    LongLineError,
+	"truncate": LongLineTruncate,
+	"skip":     LongLineSkip,
+}
+
+func ParseLongLinePolicy(s string) (LongLinePolicy, error) {
+	policy, ok := longLinePolicies[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("%v %w", s, ErrNotLongLines)
+	}
+	return policy, nil
+}
+
+// LineTooLongError сообщает о пропущенной строке. Это не ошибка чтения: ScanTickets
+// передаёт её в Diagnostics и продолжает разбор.
+type LineTooLongError struct {
+	Limit int
+}
+
+func (e *LineTooLongError) Error() string {
+	return fmt.Sprintf("%v: longer than %d bytes", ErrLineTooLong, e.Limit)
+}
+
+func (e *LineTooLongError) Unwrap() error {
+	return ErrLineTooLong
+}
+
+// ScanCRLF делит поток только по "\r\n"; одиночный "\n" остаётся внутри записи.
+var ScanCRLF = splitOn([]byte("\r\n"))
+
+// ScanNUL делит поток по нулевому байту.
+var ScanNUL = splitOn([]byte{0})
+
+var splitFuncs = map[string]bufio.SplitFunc{
+	"lines": bufio.ScanLines,
+	"crlf":  ScanCRLF,
+	"nul":   ScanNUL,
+}
+
+func NewSplitFunc(name string) (bufio.SplitFunc, error) {
+	split, ok := splitFuncs[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("%v %w", name, ErrNotSplit)
+	}
+	return split, nil
+}
+
+func SplitNames() []string {
+	names := make([]string, 0, len(splitFuncs))
+	for name := range splitFuncs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func splitOn(sep []byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.Index(data, sep); i >= 0 {
+			return i + len(sep), data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
+
+// LineOptions настраивает чтение строк. Нулевое значение совпадает с поведением bufio.Scanner:
+// строки по "\n", не длиннее DefaultMaxLineSize, длинная строка прерывает чтение.
+type LineOptions struct {
+	MaxSize   int
+	LongLines LongLinePolicy
+	Split     bufio.SplitFunc
+}
+
+// IsDefault сообщает, читает ли o строки так же, как нулевое значение.
+func (o *LineOptions) IsDefault() bool {
+	if o == nil {
+		return true
+	}
+	return o.maxSize() == DefaultMaxLineSize && o.LongLines == LongLineError &&
+		(o.Split == nil || reflect.ValueOf(o.Split).Pointer() == reflect.ValueOf(bufio.ScanLines).Pointer())
+}
+
+// longLine хранит состояние split-функции между вызовами: остаток длинной строки
+// отбрасывается, а о пропуске узнаёт читающий через skipped.
+type longLine struct {
+	discarding bool
+	skipped    bool
+}
+
+func (o *LineOptions) maxSize() int {
+	if o == nil || o.MaxSize <= 0 {
+		return DefaultMaxLineSize
+	}
+	return o.MaxSize
+}
+
+// split возвращает функцию деления по правилам o. Для LongLineError она не следит за длиной:
+// предел соблюдает тот, кто копит данные, как bufio.Scanner.
+func (o *LineOptions) split() (bufio.SplitFunc, *longLine) {
+	split := bufio.ScanLines
+	if o != nil && o.Split != nil {
+		split = o.Split
+	}
+
+	state := &longLine{}
+	if o == nil || o.LongLines == LongLineError {
+		return split, state
+	}
+	return limitSplit(split, o.maxSize(), o.LongLines, state), state
+}
+
+func (o *LineOptions) scanner(r io.Reader) (*bufio.Scanner, *longLine) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(4096, o.maxSize())), o.maxSize())
+
+	split, state := o.split()
+	scanner.Split(split)
+
+	return scanner, state
+}
+
+// limitSplit перехватывает момент, когда буфер заполнен, а split всё ещё ждёт разделителя,
+// то есть за мгновение до того, как bufio.Scanner вернул бы ErrTooLong.
+// Отбрасывая длинную строку, limitSplit оставляет вторую половину буфера: разделитель,
+// начатый в конце буфера, должен распознаться после следующего чтения.
+func limitSplit(split bufio.SplitFunc, max int, policy LongLinePolicy, state *longLine) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		advance, token, err = split(data, atEOF)
+
+		if state.discarding {
+			switch {
+			case err != nil:
+				return advance, token, err
+			case advance > 0 || token != nil:
+				state.discarding = false
+				return advance, nil, nil
+			case atEOF:
+				return len(data), nil, nil
+			case len(data) < max:
+				return 0, nil, nil
+			}
+			return len(data) - len(data)/2, nil, nil
+		}
+
+		if advance != 0 || token != nil || err != nil || len(data) < max {
+			return advance, token, err
+		}
+
+		state.discarding = true
+		if policy == LongLineSkip {
+			state.skipped = true
+			return len(data) - len(data)/2, data[:0], nil
+		}
+
+		// Не режем последний символ UTF-8 посередине.
+		n, last := max, max-1
+		for last > 0 && !utf8.RuneStart(data[last]) {
+			last--
+		}
+		if !utf8.FullRune(data[last:n]) {
+			n = last
+		}
+		return len(data) - len(data)/2, data[:n], nil
+	}
+}
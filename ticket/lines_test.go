@@ -0,0 +1,180 @@
+package ticket
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestReadLinesWith(t *testing.T) {
+	long := strings.Repeat("x", 20)
+
+	var tests = []struct {
+		name     string
+		s        string
+		options  *LineOptions
+		oneByte  bool
+		expected []Line
+	}{
+		{
+			name:     "Case default options",
+			s:        "abc\n" + long + "\ndef\n",
+			expected: []Line{{"abc", nil}, {long, nil}, {"def", nil}},
+		},
+		{
+			name:     "Case error",
+			s:        "abc\n" + long + "\ndef\n",
+			options:  &LineOptions{MaxSize: 16},
+			expected: []Line{{"abc", nil}, {"", bufio.ErrTooLong}},
+		},
+		{
+			name:     "Case truncate",
+			s:        "abc\n" + long + "\ndef\n",
+			options:  &LineOptions{MaxSize: 16, LongLines: LongLineTruncate},
+			expected: []Line{{"abc", nil}, {long[:16], nil}, {"def", nil}},
+		},
+		{
+			name:     "Case truncate without delimiter",
+			s:        "abc\n" + long,
+			options:  &LineOptions{MaxSize: 16, LongLines: LongLineTruncate},
+			expected: []Line{{"abc", nil}, {long[:16], nil}},
+		},
+		{
+			name:     "Case truncate multibyte",
+			s:        "абвгдежзий\nabc\n",
+			options:  &LineOptions{MaxSize: 15, LongLines: LongLineTruncate},
+			expected: []Line{{"абвгдеж", nil}, {"abc", nil}},
+		},
+		{
+			name:     "Case skip",
+			s:        "abc\n" + long + "\ndef\n",
+			options:  &LineOptions{MaxSize: 16, LongLines: LongLineSkip},
+			expected: []Line{{"abc", nil}, {"", &LineTooLongError{Limit: 16}}, {"def", nil}},
+		},
+		{
+			name:     "Case crlf",
+			s:        "abc\ndef\r\nghi\r\n",
+			options:  &LineOptions{Split: ScanCRLF},
+			expected: []Line{{"abc\ndef", nil}, {"ghi", nil}},
+		},
+		{
+			name:     "Case nul",
+			s:        "abc\ndef\x00ghi",
+			options:  &LineOptions{Split: ScanNUL},
+			expected: []Line{{"abc\ndef", nil}, {"ghi", nil}},
+		},
+		{
+			name:     "Case nul skip",
+			s:        long + "\x00abc\x00",
+			options:  &LineOptions{MaxSize: 8, LongLines: LongLineSkip, Split: ScanNUL},
+			expected: []Line{{"", &LineTooLongError{Limit: 8}}, {"abc", nil}},
+		},
+		{
+			name:     "Case truncate crlf split across reads",
+			s:        "xxxxxxxxxx\r\nshort\r\n",
+			options:  &LineOptions{MaxSize: 8, Split: ScanCRLF, LongLines: LongLineTruncate},
+			oneByte:  true,
+			expected: []Line{{"xxxxxxxx", nil}, {"short", nil}},
+		},
+		{
+			name:     "Case skip crlf split across reads",
+			s:        "xxxxxxxxxx\r\nshort\r\n",
+			options:  &LineOptions{MaxSize: 8, Split: ScanCRLF, LongLines: LongLineSkip},
+			oneByte:  true,
+			expected: []Line{{"", &LineTooLongError{Limit: 8}}, {"short", nil}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			var r io.Reader = strings.NewReader(test.s)
+			if test.oneByte {
+				r = iotest.OneByteReader(r)
+			}
+
+			got := make([]Line, 0)
+			for line := range ReadLinesWith(ctx, r, test.options) {
+				got = append(got, line)
+			}
+
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %q, expected %q\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestLineOptionsIsDefault(t *testing.T) {
+	var tests = []struct {
+		name     string
+		options  *LineOptions
+		expected bool
+	}{
+		{name: "Case nil", expected: true},
+		{name: "Case zero", options: &LineOptions{}, expected: true},
+		{name: "Case explicit defaults", options: &LineOptions{MaxSize: DefaultMaxLineSize, LongLines: LongLineError, Split: bufio.ScanLines}, expected: true},
+		{name: "Case max size", options: &LineOptions{MaxSize: 10}},
+		{name: "Case long lines", options: &LineOptions{LongLines: LongLineSkip}},
+		{name: "Case split", options: &LineOptions{Split: ScanCRLF}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.options.IsDefault(); got != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestScanTicketsWith(t *testing.T) {
+	s := "TICKET-1_alice_Готово_2026-01-02\n" +
+		"TICKET-2_bob_Готово_2026-01-02 " + strings.Repeat("trace ", 20) + "\n" +
+		"TICKET-3_carol_Готово_2026-01-02\n"
+
+	diagnostics := &Diagnostics{}
+	got := make([]string, 0)
+	err := ScanTicketsWith(context.Background(), strings.NewReader(s), &LineOptions{MaxSize: 64, LongLines: LongLineSkip}, nil, diagnostics, func(t *Ticket) error {
+		got = append(got, t.Ticket)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	if expected := []string{"TICKET-1", "TICKET-3"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, expected)
+	}
+	if len(diagnostics.Rejections) != 1 || diagnostics.Rejections[0].Line != 2 || diagnostics.Rejections[0].Cause != ErrLineTooLong {
+		t.Errorf("unexpected rejections: got %v\n", diagnostics.Rejections)
+	}
+}
+
+func TestNewSplitFunc(t *testing.T) {
+	for _, name := range SplitNames() {
+		if _, err := NewSplitFunc(name); err != nil {
+			t.Errorf("unexpected error for %v: %v\n", name, err)
+		}
+	}
+	if _, err := NewSplitFunc("words"); !errors.Is(err, ErrNotSplit) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, ErrNotSplit)
+	}
+
+	if policy, err := ParseLongLinePolicy(" Skip "); err != nil || policy != LongLineSkip {
+		t.Errorf("unexpected value: got %v, %v, expected %v\n", policy, err, LongLineSkip)
+	}
+	if _, err := ParseLongLinePolicy("wrap"); !errors.Is(err, ErrNotLongLines) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, ErrNotLongLines)
+	}
+}
@@ -0,0 +1,205 @@
+package ticket
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrNoSources = errors.New("no sources to read")
+
+// Source — именованный источник строк. Open вызывается воркером непосредственно перед чтением,
+// поэтому сотни файлов не держатся открытыми одновременно.
+type Source struct {
+	Name string
+	Open func() (io.ReadCloser, error)
+}
+
+func ReaderSource(name string, r io.Reader) Source {
+	return Source{Name: name, Open: func() (io.ReadCloser, error) { return io.NopCloser(r), nil }}
+}
+
+func FileSource(name string) Source {
+	return Source{Name: name, Open: func() (io.ReadCloser, error) { return os.Open(name) }}
+}
+
+func GlobSources(pattern string) ([]Source, error) {
+	names, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%v %w", pattern, ErrNoSources)
+	}
+
+	sources := make([]Source, 0, len(names))
+	for _, name := range names {
+		sources = append(sources, FileSource(name))
+	}
+	return sources, nil
+}
+
+// CompareTickets упорядочивает тикеты по дате, затем по идентификатору с учётом номера.
+func CompareTickets(a Ticket, b Ticket) int {
+	if c := a.Date.Compare(b.Date); c != 0 {
+		return c
+	}
+	return compareTicketStrings(a.Ticket, b.Ticket)
+}
+
+func scanSource(ctx context.Context, source Source, decoder TicketDecoder, filter Filter) ([]Ticket, error) {
+	r, err := source.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	tickets := make([]Ticket, 0)
+	err = ScanTickets(ctx, r, decoder, nil, func(t *Ticket) error {
+		if filter.Match(t) {
+			tickets = append(tickets, *t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(tickets, CompareTickets)
+	return tickets, nil
+}
+
+// MergeTickets разбирает источники параллельно не более чем workers воркерами
+// и возвращает тикеты, упорядоченные CompareTickets. Первая ошибка отменяет остальные источники.
+func MergeTickets(ctx context.Context, sources []Source, decoder TicketDecoder, filter Filter, workers int) ([]Ticket, error) {
+	if len(sources) == 0 {
+		return nil, ErrNoSources
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	results := make([][]Ticket, len(sources))
+	jobs := make(chan int)
+
+	for range min(workers, len(sources)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				tickets, err := scanSource(ctx, sources[i], decoder, filter)
+				if err != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("%v: %w", sources[i].Name, err)
+						cancel()
+					})
+					continue
+				}
+				results[i] = tickets
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range sources {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- i:
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return mergeSorted(results), nil
+}
+
+type mergeCursor struct {
+	source  int
+	tickets []Ticket
+}
+
+type mergeHeap []mergeCursor
+
+func (h mergeHeap) Len() int { return len(h) }
+
+func (h mergeHeap) Less(i int, j int) bool {
+	if c := CompareTickets(h[i].tickets[0], h[j].tickets[0]); c != 0 {
+		return c < 0
+	}
+	return h[i].source < h[j].source
+}
+
+func (h mergeHeap) Swap(i int, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(x any) { *h = append(*h, x.(mergeCursor)) }
+
+func (h *mergeHeap) Pop() any {
+	old := *h
+	cursor := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return cursor
+}
+
+// mergeSorted сливает уже упорядоченные срезы, сохраняя порядок источников при равенстве.
+func mergeSorted(results [][]Ticket) []Ticket {
+	total := 0
+	h := make(mergeHeap, 0, len(results))
+	for i, tickets := range results {
+		total += len(tickets)
+		if len(tickets) > 0 {
+			h = append(h, mergeCursor{source: i, tickets: tickets})
+		}
+	}
+	heap.Init(&h)
+
+	merged := make([]Ticket, 0, total)
+	for h.Len() > 0 {
+		merged = append(merged, h[0].tickets[0])
+		h[0].tickets = h[0].tickets[1:]
+		if len(h[0].tickets) == 0 {
+			heap.Pop(&h)
+		} else {
+			heap.Fix(&h, 0)
+		}
+	}
+
+	return merged
+}
+
+func GetTasksMerged(ctx context.Context, sources []Source, w io.Writer, decoder TicketDecoder, filter Filter, workers int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tickets, err := MergeTickets(ctx, sources, decoder, filter, workers)
+	if err != nil {
+		return err
+	}
+
+	return WriteTicketsJSON(w, tickets)
+}
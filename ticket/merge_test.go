@@ -0,0 +1,192 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+func TestMergeTickets(t *testing.T) {
+	var (
+		day1 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		day2 = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		day3 = time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+	)
+
+	sources := func() []Source {
+		return []Source{
+			ReaderSource("second", strings.NewReader("TICKET-4_bob_Готово_2026-01-03\nTICKET-2_bob_В работе_2026-01-02\n")),
+			ReaderSource("first", strings.NewReader("TICKET-3_alice_Готово_2026-01-01\nTICKET-1_alice_В работе_2026-01-02\nsome-text\n")),
+			ReaderSource("empty", strings.NewReader("")),
+		}
+	}
+
+	var tests = []struct {
+		name        string
+		sources     []Source
+		workers     int
+		timeout     time.Duration
+		expected    []Ticket
+		expectedErr error
+	}{
+		{
+			name:    "Case ordered merge",
+			sources: sources(),
+			workers: 2,
+			timeout: 10 * time.Millisecond,
+			expected: []Ticket{
+				{Ticket: "TICKET-3", User: "alice", Status: "Готово", Date: day1},
+				{Ticket: "TICKET-1", User: "alice", Status: "В работе", Date: day2},
+				{Ticket: "TICKET-2", User: "bob", Status: "В работе", Date: day2},
+				{Ticket: "TICKET-4", User: "bob", Status: "Готово", Date: day3},
+			},
+		},
+		{
+			name:    "Case single worker",
+			sources: sources(),
+			workers: 0,
+			timeout: 10 * time.Millisecond,
+			expected: []Ticket{
+				{Ticket: "TICKET-3", User: "alice", Status: "Готово", Date: day1},
+				{Ticket: "TICKET-1", User: "alice", Status: "В работе", Date: day2},
+				{Ticket: "TICKET-2", User: "bob", Status: "В работе", Date: day2},
+				{Ticket: "TICKET-4", User: "bob", Status: "Готово", Date: day3},
+			},
+		},
+		{
+			name:        "Case no sources",
+			workers:     2,
+			timeout:     10 * time.Millisecond,
+			expectedErr: ErrNoSources,
+		},
+		{
+			name: "Case read error",
+			sources: append(sources(), ReaderSource("broken", testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				n = copy(p, []byte("TICKET-5_carol_Готово_2026-01-01\n"))
+				return n, testutils.FakeReadError
+			}))),
+			workers:     4,
+			timeout:     10 * time.Millisecond,
+			expectedErr: testutils.FakeReadError,
+		},
+		{
+			name: "Case open error",
+			sources: append(sources(), Source{Name: "closed", Open: func() (io.ReadCloser, error) {
+				return nil, testutils.FakeReadError
+			}}),
+			workers:     1,
+			timeout:     10 * time.Millisecond,
+			expectedErr: testutils.FakeReadError,
+		},
+		{
+			name: "Case slow source",
+			sources: append(sources(), ReaderSource("slow",
+				testutils.NewDelayReader(context.Background(), strings.NewReader(""), 10*time.Second))),
+			workers:     2,
+			timeout:     10 * time.Millisecond,
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+			defer cancel()
+
+			start := time.Now()
+			got, err := MergeTickets(ctx, test.sources, DefaultDecoder, nil, test.workers)
+			duration := time.Since(start)
+
+			// Медленный источник отвечает через 10s: MergeTickets должен вернуться по ctx, не дожидаясь его.
+			if limit := test.timeout + time.Second; duration > limit {
+				t.Errorf("unexpected execution time: got %v, expected less than %v\n", duration, limit)
+			}
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+
+			b, _ := json.Marshal(got)
+			expected, _ := json.Marshal(test.expected)
+			if string(b) != string(expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", string(b), string(expected))
+			}
+		})
+	}
+}
+
+func TestMergeTicketsWorkers(t *testing.T) {
+	var running, peak atomic.Int32
+
+	sources := make([]Source, 0)
+	for i := range 20 {
+		line := fmt.Sprintf("TICKET-%d_user_Готово_2026-01-%02d\n", i, i%28+1)
+		sources = append(sources, Source{Name: fmt.Sprint(i), Open: func() (io.ReadCloser, error) {
+			current := running.Add(1)
+			for old := peak.Load(); current > old && !peak.CompareAndSwap(old, current); old = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			return readCloser{Reader: strings.NewReader(line), close: func() error { running.Add(-1); return nil }}, nil
+		}})
+	}
+
+	got, err := MergeTickets(context.Background(), sources, DefaultDecoder, nil, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	if len(got) != 20 {
+		t.Errorf("unexpected number of tickets: got %v, expected %v\n", len(got), 20)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("unexpected number of concurrent sources: got %v, expected at most %v\n", p, 3)
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
+
+func TestGetTasksMerged(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "2026-01-02.log"), []byte("TICKET-2_bob_Готово_2026-01-02\n"), 0666)
+	_ = os.WriteFile(filepath.Join(dir, "2026-01-01.log"), []byte("TICKET-1_alice_Готово_2026-01-01\n"), 0666)
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("TICKET-0_alice_Готово_2025-01-01\n"), 0666)
+
+	sources, err := GlobSources(filepath.Join(dir, "*.log"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	w := bytes.NewBuffer(nil)
+	if err := GetTasksMerged(context.Background(), sources, w, DefaultDecoder, nil, 2, 10*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	expected := `[{"Ticket":"TICKET-1","User":"alice","Status":"Готово","Date":"2026-01-01T00:00:00Z"},` +
+		`{"Ticket":"TICKET-2","User":"bob","Status":"Готово","Date":"2026-01-02T00:00:00Z"}]`
+	if got := w.String(); got != expected {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, expected)
+	}
+
+	if _, err := GlobSources(filepath.Join(dir, "*.csv")); !errors.Is(err, ErrNoSources) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, ErrNoSources)
+	}
+}
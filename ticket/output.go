@@ -0,0 +1,67 @@
+package ticket
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+var OutputFormats = []string{"json", "csv", "table", "ics", "markdown"}
+
+func WriteTicketsJSON(w io.Writer, tickets []Ticket) error {
+	if tickets == nil {
+		tickets = make([]Ticket, 0)
+	}
+	b, err := json.Marshal(tickets)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func WriteTicketsCSV(w io.Writer, tickets []Ticket) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"ticket", "user", "status", "date"}); err != nil {
+		return err
+	}
+	for _, t := range tickets {
+		if err := writer.Write([]string{t.Ticket, t.User, t.Status, t.Date.Format(DateLayout)}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func WriteTicketsTable(w io.Writer, tickets []Ticket) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ticket\tuser\tstatus\tdate")
+	for _, t := range tickets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Ticket, t.User, t.Status, t.Date.Format(DateLayout))
+	}
+
+	return tw.Flush()
+}
+
+func WriteTickets(w io.Writer, tickets []Ticket, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		return WriteTicketsJSON(w, tickets)
+	case "csv":
+		return WriteTicketsCSV(w, tickets)
+	case "table":
+		return WriteTicketsTable(w, tickets)
+	case "ics":
+		return WriteTicketsICal(w, tickets)
+	case "markdown":
+		return WriteTicketsMarkdown(w, tickets)
+	}
+	return fmt.Errorf("%v %w", format, ErrNotOutput)
+}
@@ -0,0 +1,155 @@
+package ticket
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteTickets(t *testing.T) {
+	var tickets = []Ticket{
+		{Ticket: "TICKET-1", User: "alice", Status: "Готово", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Ticket: "TICKET-22", User: "bob", Status: "В работе", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var tests = []struct {
+		name        string
+		tickets     []Ticket
+		format      string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Case json",
+			tickets:  tickets,
+			format:   "json",
+			expected: `[{"Ticket":"TICKET-1","User":"alice","Status":"Готово","Date":"2026-01-02T00:00:00Z"},{"Ticket":"TICKET-22","User":"bob","Status":"В работе","Date":"2026-01-03T00:00:00Z"}]`,
+		},
+		{
+			name:     "Case json empty",
+			format:   "json",
+			expected: `[]`,
+		},
+		{
+			name:     "Case csv",
+			tickets:  tickets,
+			format:   "csv",
+			expected: "ticket,user,status,date\nTICKET-1,alice,Готово,2026-01-02\nTICKET-22,bob,В работе,2026-01-03\n",
+		},
+		{
+			name:    "Case table",
+			tickets: tickets,
+			format:  "table",
+			expected: strings.Join([]string{
+				"ticket     user   status    date",
+				"TICKET-1   alice  Готово    2026-01-02",
+				"TICKET-22  bob    В работе  2026-01-03",
+			}, "\n") + "\n",
+		},
+		{
+			name:    "Case ics",
+			tickets: tickets,
+			format:  "ics",
+			expected: strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//go-2-step-by-step//tickets//RU",
+				"BEGIN:VTODO",
+				"UID:TICKET-1-1767312000@tickets",
+				"DTSTAMP:20260102T000000Z",
+				"DTSTART:20260102T000000Z",
+				"SUMMARY:TICKET-1",
+				"DESCRIPTION:alice: Готово",
+				"CATEGORIES:alice",
+				"STATUS:COMPLETED",
+				"COMPLETED:20260102T000000Z",
+				"END:VTODO",
+				"BEGIN:VTODO",
+				"UID:TICKET-22-1767398400@tickets",
+				"DTSTAMP:20260103T000000Z",
+				"DTSTART:20260103T000000Z",
+				"SUMMARY:TICKET-22",
+				"DESCRIPTION:bob: В работе",
+				"CATEGORIES:bob",
+				"STATUS:IN-PROCESS",
+				"END:VTODO",
+				"END:VCALENDAR",
+			}, "\r\n") + "\r\n",
+		},
+		{
+			name:     "Case ics empty",
+			format:   "ics",
+			expected: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//go-2-step-by-step//tickets//RU\r\nEND:VCALENDAR\r\n",
+		},
+		{
+			name: "Case markdown",
+			tickets: append(slices.Clone(tickets),
+				Ticket{Ticket: "TICKET-3", User: "alice", Status: "Не будет сделано", Date: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+				Ticket{Ticket: "TICKET-4", User: "a_b", Status: "Done", Date: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+			),
+			format: "markdown",
+			expected: strings.Join([]string{
+				"## a\\_b",
+				"",
+				"- [x] TICKET-4 — Done, 2026-01-05",
+				"",
+				"## alice",
+				"",
+				"- [x] TICKET-1 — Готово, 2026-01-02",
+				"- [x] ~~TICKET-3~~ — Не будет сделано, 2026-01-04",
+				"",
+				"## bob",
+				"",
+				"- [ ] TICKET-22 — В работе, 2026-01-03",
+			}, "\n") + "\n",
+		},
+		{
+			name:        "Case unknown format",
+			tickets:     tickets,
+			format:      "yaml",
+			expectedErr: ErrNotOutput,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := bytes.NewBuffer(nil)
+			err := WriteTickets(w, test.tickets, test.format)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if got := w.String(); got != test.expected {
+				t.Errorf("unexpected value: got\n%v\nexpected\n%v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestWriteTicketsICalFolding(t *testing.T) {
+	tickets := []Ticket{{Ticket: "TICKET-1", User: strings.Repeat("пользователь; ", 8), Status: "?", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}}
+
+	w := bytes.NewBuffer(nil)
+	if err := WriteTicketsICal(w, tickets); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	unfolded := strings.ReplaceAll(w.String(), "\r\n ", "")
+	for _, line := range strings.Split(strings.TrimSuffix(w.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Errorf("unexpected line of %d bytes: %q\n", len(line), line)
+		}
+	}
+	if expected := "CATEGORIES:" + strings.Repeat(`пользователь\; `, 8) + "\r\n"; !strings.Contains(unfolded, expected) {
+		t.Errorf("unexpected value: got\n%v\nexpected line %q\n", unfolded, expected)
+	}
+	if !strings.Contains(unfolded, "STATUS:NEEDS-ACTION\r\n") {
+		t.Errorf("unexpected status in\n%v\n", unfolded)
+	}
+}
@@ -0,0 +1,136 @@
+package ticket
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var ErrStatusConfig = errors.New("is not a status definition")
+
+// StatusRegistry сопоставляет псевдонимы статусов каноническим значениям Status.
+// Сравнение не учитывает регистр, лишние пробелы и вид апострофа: "won’t  DO" совпадает с "Won't do".
+// Методы безопасны для одновременного вызова.
+type StatusRegistry struct {
+	mu       sync.RWMutex
+	aliases  map[string]Status
+	statuses []Status
+}
+
+func NewStatusRegistry() *StatusRegistry {
+	return &StatusRegistry{aliases: make(map[string]Status)}
+}
+
+// DefaultStatusRegistry знает три статуса трекера с английскими псевдонимами из экспортов.
+func DefaultStatusRegistry() *StatusRegistry {
+	registry := NewStatusRegistry()
+	registry.Register(Ready, "Done", "Ready", "Completed", "Сделано")
+	registry.Register(InProgress, "In progress", "In-progress", "Doing", "В процессе")
+	registry.Register(WillNotBeDone, "Won't do", "Will not be done", "Wontfix", "Отменено")
+	return registry
+}
+
+// Statuses — реестр, с которым сверяются IsStatus, NewTicket, ParseTicket и декодеры.
+var Statuses = DefaultStatusRegistry()
+
+func normalizeStatus(s string) string {
+	s = strings.NewReplacer("’", "'", "‘", "'", "`", "'").Replace(s)
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Register добавляет статус и его псевдонимы. Канонический статус всегда является псевдонимом самого себя.
+// Псевдоним, уже принадлежащий другому статусу, переназначается.
+func (r *StatusRegistry) Register(status Status, aliases ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.aliases[normalizeStatus(string(status))]; !ok {
+		r.statuses = append(r.statuses, status)
+	}
+	r.aliases[normalizeStatus(string(status))] = status
+	for _, alias := range aliases {
+		r.aliases[normalizeStatus(alias)] = status
+	}
+}
+
+func (r *StatusRegistry) Lookup(s string) (Status, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status, ok := r.aliases[normalizeStatus(s)]
+	return status, ok
+}
+
+// identity описывает содержимое реестра: пары «псевдоним=статус» в порядке сортировки.
+func (r *StatusRegistry) identity() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pairs := make([]string, 0, len(r.aliases))
+	for alias, status := range r.aliases {
+		pairs = append(pairs, alias+"="+string(status))
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Statuses возвращает канонические статусы в порядке регистрации.
+func (r *StatusRegistry) Statuses() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Status(nil), r.statuses...)
+}
+
+// Load читает определения статусов по одному на строку:
+//
+//	# комментарий
+//	Готово = Done, Ready, Сделано
+//	В работе = In progress
+//
+// Слева статус, справа псевдонимы через запятую. Если статус слева уже известен под любым именем,
+// псевдонимы добавляются к нему. Пустые строки и комментарии пропускаются.
+func (r *StatusRegistry) Load(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		status, aliases, _ := strings.Cut(line, "=")
+		status = strings.TrimSpace(status)
+		if status == "" {
+			return fmt.Errorf("line %d: %q %w", number, line, ErrStatusConfig)
+		}
+
+		names := make([]string, 0)
+		for _, alias := range strings.Split(aliases, ",") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				names = append(names, alias)
+			}
+		}
+		canonical, ok := r.Lookup(status)
+		if !ok {
+			canonical = Status(status)
+		}
+		r.Register(canonical, names...)
+	}
+
+	return scanner.Err()
+}
+
+func (r *StatusRegistry) LoadFile(name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return r.Load(file)
+}
@@ -0,0 +1,76 @@
+package ticket
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusRegistry(t *testing.T) {
+	registry := DefaultStatusRegistry()
+	err := registry.Load(strings.NewReader(strings.Join([]string{
+		"# aliases from the German export",
+		"",
+		"Готово = Erledigt, Fertig",
+		"done = Abgeschlossen",
+		"На проверке = Review, In review",
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	var tests = []struct {
+		name     string
+		s        string
+		expected Status
+		ok       bool
+	}{
+		{name: "Case canonical", s: "Готово", expected: Ready, ok: true},
+		{name: "Case upper case", s: "ГОТОВО", expected: Ready, ok: true},
+		{name: "Case whitespace", s: "  В   работе\t", expected: InProgress, ok: true},
+		{name: "Case english", s: "in PROGRESS", expected: InProgress, ok: true},
+		{name: "Case apostrophe", s: "Won’t do", expected: WillNotBeDone, ok: true},
+		{name: "Case loaded alias", s: "fertig", expected: Ready, ok: true},
+		{name: "Case alias of alias", s: "Abgeschlossen", expected: Ready, ok: true},
+		{name: "Case new status", s: "in review", expected: "На проверке", ok: true},
+		{name: "Case unknown", s: "Готово2", ok: false},
+		{name: "Case empty", s: "", ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := registry.Lookup(test.s)
+			if got != test.expected || ok != test.ok {
+				t.Errorf("unexpected value for %v: got %v %v, expected %v %v\n", test.s, got, ok, test.expected, test.ok)
+			}
+		})
+	}
+
+	expected := []Status{Ready, InProgress, WillNotBeDone, "На проверке"}
+	if got := registry.Statuses(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected statuses: got %v, expected %v\n", got, expected)
+	}
+}
+
+func TestStatusRegistryLoadError(t *testing.T) {
+	err := NewStatusRegistry().Load(strings.NewReader("Готово = Done\n= Ready\n"))
+	if !errors.Is(err, ErrStatusConfig) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, ErrStatusConfig)
+	}
+}
+
+func TestParseTicketAlias(t *testing.T) {
+	got, err := ParseTicket("TICKET-1_user_ won't DO _2026-01-03", "_", DateLayout)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	expected := &Ticket{Ticket: "TICKET-1", User: "user", Status: string(WillNotBeDone), Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, expected)
+	}
+}
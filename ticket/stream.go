@@ -0,0 +1,125 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Trailer дописывается после закрывающей скобки, если вывод был прерван.
+type Trailer struct {
+	Truncated bool
+	Count     int
+	Reason    string
+}
+
+type TicketStream struct {
+	w      io.Writer
+	flush  func() error
+	count  int
+	opened bool
+	closed bool
+}
+
+func NewTicketStream(w io.Writer, flush func() error) *TicketStream {
+	return &TicketStream{w: w, flush: flush}
+}
+
+func (s *TicketStream) Count() int {
+	return s.count
+}
+
+func (s *TicketStream) write(b []byte) error {
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+	if s.flush != nil {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *TicketStream) Open() error {
+	if s.opened {
+		return nil
+	}
+	s.opened = true
+	return s.write([]byte("["))
+}
+
+func (s *TicketStream) Write(t Ticket) error {
+	if err := s.Open(); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if s.count > 0 {
+		b = append([]byte(","), b...)
+	}
+
+	if err := s.write(b); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+func (s *TicketStream) Close() error {
+	if s.closed {
+		return nil
+	}
+	if err := s.Open(); err != nil {
+		return err
+	}
+	s.closed = true
+	return s.write([]byte("]"))
+}
+
+func (s *TicketStream) Abort(reason error) error {
+	if s.closed {
+		return nil
+	}
+	if err := s.Close(); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(Trailer{Truncated: true, Count: s.count, Reason: reason.Error()})
+	if err != nil {
+		return err
+	}
+	b = append([]byte("\n"), b...)
+	return s.write(b)
+}
+
+func StreamTasks(ctx context.Context, r io.Reader, w io.Writer, decoder TicketDecoder, filter Filter, timeout time.Duration, flush func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stream := NewTicketStream(w, flush)
+	if err := stream.Open(); err != nil {
+		return err
+	}
+
+	var writeErr error
+	err := ScanTickets(ctx, r, decoder, nil, func(t *Ticket) error {
+		if filter.Match(t) {
+			writeErr = stream.Write(*t)
+		}
+		return writeErr
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		if err := stream.Abort(err); err != nil {
+			return err
+		}
+		return err
+	}
+
+	return stream.Close()
+}
@@ -0,0 +1,204 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
+)
+
+func TestTicketStream(t *testing.T) {
+	var (
+		first  = Ticket{Ticket: "TICKET-12345", User: "user", Status: "Готово", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+		second = Ticket{Ticket: "TICKET-12346", User: "user", Status: "В работе", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)}
+	)
+
+	var tests = []struct {
+		name     string
+		tickets  []Ticket
+		abort    error
+		expected string
+	}{
+		{
+			name:     "Case empty",
+			expected: "[]",
+		},
+		{
+			name:    "Case two tickets",
+			tickets: []Ticket{first, second},
+			expected: func() string {
+				s, _ := json.Marshal([]Ticket{first, second})
+				return string(s)
+			}(),
+		},
+		{
+			name:    "Case aborted",
+			tickets: []Ticket{first},
+			abort:   context.DeadlineExceeded,
+			expected: func() string {
+				s, _ := json.Marshal([]Ticket{first})
+				return string(s) + "\n" + `{"Truncated":true,"Count":1,"Reason":"context deadline exceeded"}`
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := bytes.NewBuffer(nil)
+			flushes := 0
+			stream := NewTicketStream(w, func() error { flushes++; return nil })
+
+			for _, ticket := range test.tickets {
+				if err := stream.Write(ticket); err != nil {
+					t.Fatalf("unexpected error: %v\n", err)
+				}
+			}
+
+			var err error
+			if test.abort != nil {
+				err = stream.Abort(test.abort)
+			} else {
+				err = stream.Close()
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+
+			if got := w.String(); got != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+			if flushes == 0 {
+				t.Errorf("flush was not called\n")
+			}
+		})
+	}
+}
+
+func TestStreamTasks(t *testing.T) {
+	var user string = "user"
+
+	var lines = []string{
+		"TICKET-12345_user_Готово_2026-01-02",
+		"TICKET-12346_user_В работе_2026-01-03",
+		"invalid-ticket_user_Готово_2026-01-03",
+		"TICKET-12355_another_Готово_2026-01-02",
+	}
+
+	var tests = []struct {
+		name        string
+		reader      io.Reader
+		user        *string
+		timeout     time.Duration
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:    "Case same as GetTasks",
+			reader:  strings.NewReader(strings.Join(lines, "\n")),
+			user:    &user,
+			timeout: 10 * time.Millisecond,
+			expected: func() string {
+				w := bytes.NewBuffer(nil)
+				_ = GetTasks(context.Background(), strings.NewReader(strings.Join(lines, "\n")), w, DefaultDecoder, TargetFilter(&user, nil), 10*time.Millisecond)
+				return w.String()
+			}(),
+		},
+		{
+			name:     "Case empty reader",
+			reader:   strings.NewReader(""),
+			timeout:  10 * time.Millisecond,
+			expected: "[]",
+		},
+		{
+			name: "Case read error",
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				n = copy(p, []byte("TICKET-12345_user_Готово_2026-01-02\n"))
+				return n, testutils.FakeReadError
+			}),
+			timeout:     10 * time.Millisecond,
+			expected:    `[]` + "\n" + `{"Truncated":true,"Count":0,"Reason":"fake read error"}`,
+			expectedErr: testutils.FakeReadError,
+		},
+		{
+			name: "Case delation",
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				time.Sleep(5 * time.Second)
+				n = copy(p, []byte("TICKET-12345_user_Готово_2026-01-02\n"))
+				return n, err
+			}),
+			timeout:     10 * time.Millisecond,
+			expected:    `[]` + "\n" + `{"Truncated":true,"Count":0,"Reason":"context deadline exceeded"}`,
+			expectedErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := bytes.NewBuffer(nil)
+
+			start := time.Now()
+			err := StreamTasks(context.Background(), test.reader, w, DefaultDecoder, TargetFilter(test.user, nil), test.timeout, nil)
+			duration := time.Since(start)
+
+			// Медленный источник спит 5s, поэтому с запасом в секунду видно, что StreamTasks его не ждёт.
+			if limit := test.timeout + time.Second; duration > limit {
+				t.Errorf("unexpected execution time: got %v, expected less than %v\n", duration, limit)
+			}
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+
+			if got := w.String(); got != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestStreamTasksPartial(t *testing.T) {
+	sent := false
+	reader := testutils.ReaderFunc(func(p []byte) (n int, err error) {
+		if sent {
+			time.Sleep(20 * time.Millisecond)
+			return 0, nil
+		}
+		sent = true
+		n = copy(p, []byte("TICKET-12345_user_Готово_2026-01-02\n"))
+		return n, nil
+	})
+
+	w := bytes.NewBuffer(nil)
+	err := StreamTasks(context.Background(), reader, w, DefaultDecoder, nil, 10*time.Millisecond, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, context.DeadlineExceeded)
+	}
+
+	decoder := json.NewDecoder(w)
+
+	var tickets []Ticket
+	if err := decoder.Decode(&tickets); err != nil {
+		t.Fatalf("partial result is not well-formed: %v\n", err)
+	}
+	if len(tickets) != 1 {
+		t.Errorf("unexpected number of tickets: got %v, expected %v\n", len(tickets), 1)
+	}
+
+	var trailer Trailer
+	if err := decoder.Decode(&trailer); err != nil {
+		t.Fatalf("trailer is not well-formed: %v\n", err)
+	}
+	if !trailer.Truncated || trailer.Count != 1 {
+		t.Errorf("unexpected trailer: got %+v\n", trailer)
+	}
+}
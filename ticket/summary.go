@@ -0,0 +1,221 @@
+package ticket
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	ErrNotBucket = errors.New("is not a date bucket")
+	ErrNotOutput = errors.New("is not an output format")
+)
+
+type Bucket int
+
+const (
+	DayBucket Bucket = iota
+	WeekBucket
+	MonthBucket
+)
+
+var bucketNames = map[Bucket]string{DayBucket: "day", WeekBucket: "week", MonthBucket: "month"}
+
+func ParseBucket(s string) (Bucket, error) {
+	for bucket, name := range bucketNames {
+		if strings.EqualFold(strings.TrimSpace(s), name) {
+			return bucket, nil
+		}
+	}
+	return DayBucket, fmt.Errorf("%v %w", s, ErrNotBucket)
+}
+
+func (b Bucket) String() string {
+	return bucketNames[b]
+}
+
+func (b Bucket) Key(date time.Time) string {
+	switch b {
+	case WeekBucket:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case MonthBucket:
+		return date.Format("2006-01")
+	default:
+		return date.Format(DateLayout)
+	}
+}
+
+type Summary struct {
+	Bucket   string
+	Total    int
+	ByUser   map[string]int
+	ByStatus map[string]int
+	ByDate   map[string]int
+	Pivot    map[string]map[string]int
+
+	bucket Bucket
+}
+
+func NewSummary(bucket Bucket) *Summary {
+	return &Summary{
+		Bucket:   bucket.String(),
+		ByUser:   make(map[string]int),
+		ByStatus: make(map[string]int),
+		ByDate:   make(map[string]int),
+		Pivot:    make(map[string]map[string]int),
+		bucket:   bucket,
+	}
+}
+
+func (s *Summary) Add(t *Ticket) {
+	s.Total++
+	s.ByUser[t.User]++
+	s.ByStatus[t.Status]++
+	s.ByDate[s.bucket.Key(t.Date)]++
+
+	if s.Pivot[t.User] == nil {
+		s.Pivot[t.User] = make(map[string]int)
+	}
+	s.Pivot[t.User][t.Status]++
+}
+
+func Summarize(ctx context.Context, r io.Reader, decoder TicketDecoder, filter Filter, bucket Bucket, timeout time.Duration) (*Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	summary := NewSummary(bucket)
+	err := ScanTickets(ctx, r, decoder, nil, func(t *Ticket) error {
+		if filter.Match(t) {
+			summary.Add(t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// statuses возвращает статусы сводки: сначала известные реестру в порядке регистрации, затем остальные по алфавиту.
+func (s *Summary) statuses() []string {
+	known := make([]string, 0)
+	for _, status := range Statuses.Statuses() {
+		known = append(known, string(status))
+	}
+
+	statuses := make([]string, 0, len(s.ByStatus))
+	for _, status := range known {
+		if _, found := s.ByStatus[status]; found {
+			statuses = append(statuses, status)
+		}
+	}
+	for _, status := range sortedKeys(s.ByStatus) {
+		if !slices.Contains(known, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+func (s *Summary) WriteJSON(w io.Writer) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// WriteCSV пишет сводку в длинном формате: dimension,user,status,bucket,count.
+func (s *Summary) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	records := [][]string{{"dimension", "user", "status", s.Bucket, "count"}}
+	records = append(records, []string{"total", "", "", "", strconv.Itoa(s.Total)})
+	for _, user := range sortedKeys(s.ByUser) {
+		records = append(records, []string{"user", user, "", "", strconv.Itoa(s.ByUser[user])})
+	}
+	for _, status := range s.statuses() {
+		records = append(records, []string{"status", "", status, "", strconv.Itoa(s.ByStatus[status])})
+	}
+	for _, key := range sortedKeys(s.ByDate) {
+		records = append(records, []string{s.Bucket, "", "", key, strconv.Itoa(s.ByDate[key])})
+	}
+	for _, user := range sortedKeys(s.ByUser) {
+		for _, status := range s.statuses() {
+			if count := s.Pivot[user][status]; count > 0 {
+				records = append(records, []string{"pivot", user, status, "", strconv.Itoa(count)})
+			}
+		}
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func (s *Summary) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	writeSection := func(title string, counts map[string]int, keys []string) {
+		fmt.Fprintf(tw, "%s\tcount\n", title)
+		for _, key := range keys {
+			fmt.Fprintf(tw, "%s\t%d\n", key, counts[key])
+		}
+		fmt.Fprintln(tw)
+	}
+
+	writeSection("user", s.ByUser, sortedKeys(s.ByUser))
+	writeSection("status", s.ByStatus, s.statuses())
+	writeSection(s.Bucket, s.ByDate, sortedKeys(s.ByDate))
+
+	statuses := s.statuses()
+	fmt.Fprintf(tw, "user\t%s\ttotal\n", strings.Join(statuses, "\t"))
+	for _, user := range sortedKeys(s.ByUser) {
+		fmt.Fprintf(tw, "%s", user)
+		for _, status := range statuses {
+			fmt.Fprintf(tw, "\t%d", s.Pivot[user][status])
+		}
+		fmt.Fprintf(tw, "\t%d\n", s.ByUser[user])
+	}
+	fmt.Fprintf(tw, "total")
+	for _, status := range statuses {
+		fmt.Fprintf(tw, "\t%d", s.ByStatus[status])
+	}
+	fmt.Fprintf(tw, "\t%d\n", s.Total)
+
+	return tw.Flush()
+}
+
+func WriteSummary(w io.Writer, s *Summary, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		return s.WriteJSON(w)
+	case "csv":
+		return s.WriteCSV(w)
+	case "table":
+		return s.WriteTable(w)
+	}
+	return fmt.Errorf("%v %w", format, ErrNotOutput)
+}
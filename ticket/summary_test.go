@@ -0,0 +1,181 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var summaryLines = []string{
+	"TICKET-1_alice_Готово_2026-01-02",
+	"TICKET-2_alice_В работе_2026-01-05",
+	"TICKET-3_bob_Готово_2026-01-05",
+	"TICKET-4_bob_Не будет сделано_2026-02-01",
+	"invalid-ticket_bob_Готово_2026-01-03",
+}
+
+func TestBucketKey(t *testing.T) {
+	date := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		name     string
+		bucket   string
+		expected string
+	}{
+		{name: "Case day", bucket: "day", expected: "2026-01-05"},
+		{name: "Case week", bucket: "Week", expected: "2026-W02"},
+		{name: "Case month", bucket: "month", expected: "2026-01"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			bucket, err := ParseBucket(test.bucket)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+			if got := bucket.Key(date); got != test.expected {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+
+	if _, err := ParseBucket("year"); !errors.Is(err, ErrNotBucket) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, ErrNotBucket)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summary, err := Summarize(context.Background(), strings.NewReader(strings.Join(summaryLines, "\n")), DefaultDecoder, nil, WeekBucket, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	if summary.Total != 4 {
+		t.Errorf("unexpected total: got %v, expected %v\n", summary.Total, 4)
+	}
+	if expected := map[string]int{"alice": 2, "bob": 2}; !reflect.DeepEqual(summary.ByUser, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", summary.ByUser, expected)
+	}
+	if expected := map[string]int{"Готово": 2, "В работе": 1, "Не будет сделано": 1}; !reflect.DeepEqual(summary.ByStatus, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", summary.ByStatus, expected)
+	}
+	if expected := map[string]int{"2026-W01": 1, "2026-W02": 2, "2026-W05": 1}; !reflect.DeepEqual(summary.ByDate, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", summary.ByDate, expected)
+	}
+	expected := map[string]map[string]int{
+		"alice": {"Готово": 1, "В работе": 1},
+		"bob":   {"Готово": 1, "Не будет сделано": 1},
+	}
+	if !reflect.DeepEqual(summary.Pivot, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", summary.Pivot, expected)
+	}
+}
+
+func TestSummarizeLocation(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	decoder := WithDates(DefaultDecoder, DateParser{Location: moscow})
+	lines := "TICKET-1_alice_Готово_2026-01-31T22:30:00Z\nTICKET-2_bob_Готово_2026-02-01 00:30:00\n"
+
+	summary, err := Summarize(context.Background(), strings.NewReader(lines), decoder, nil, DayBucket, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	if expected := map[string]int{"2026-02-01": 2}; !reflect.DeepEqual(summary.ByDate, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", summary.ByDate, expected)
+	}
+
+	b := bytes.NewBuffer(nil)
+	theTicket, _ := decoder.Decode("TICKET-1_alice_Готово_2026-01-31T22:30:00Z")
+	if err := WriteTicketsCSV(b, []Ticket{*theTicket}); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	if expected := "ticket,user,status,date\nTICKET-1,alice,Готово,2026-02-01\n"; b.String() != expected {
+		t.Errorf("unexpected value: got %q, expected %q\n", b.String(), expected)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	filter, _ := CompileFilter("date < 2026-02-01")
+	summary, err := Summarize(context.Background(), strings.NewReader(strings.Join(summaryLines, "\n")), DefaultDecoder, filter, MonthBucket, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	var tests = []struct {
+		name        string
+		format      string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:   "Case json",
+			format: "json",
+			expected: `{"Bucket":"month","Total":3,"ByUser":{"alice":2,"bob":1},"ByStatus":{"В работе":1,"Готово":2},` +
+				`"ByDate":{"2026-01":3},"Pivot":{"alice":{"В работе":1,"Готово":1},"bob":{"Готово":1}}}`,
+		},
+		{
+			name:   "Case csv",
+			format: "csv",
+			expected: strings.Join([]string{
+				"dimension,user,status,month,count",
+				"total,,,,3",
+				"user,alice,,,2",
+				"user,bob,,,1",
+				"status,,Готово,,2",
+				"status,,В работе,,1",
+				"month,,,2026-01,3",
+				"pivot,alice,Готово,,1",
+				"pivot,alice,В работе,,1",
+				"pivot,bob,Готово,,1",
+			}, "\n") + "\n",
+		},
+		{
+			name:   "Case table",
+			format: "table",
+			expected: strings.Join([]string{
+				"user   count",
+				"alice  2",
+				"bob    1",
+				"",
+				"status    count",
+				"Готово    2",
+				"В работе  1",
+				"",
+				"month    count",
+				"2026-01  3",
+				"",
+				"user   Готово  В работе  total",
+				"alice  1       1         2",
+				"bob    1       0         1",
+				"total  2       1         3",
+			}, "\n") + "\n",
+		},
+		{
+			name:        "Case unknown format",
+			format:      "xml",
+			expectedErr: ErrNotOutput,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := bytes.NewBuffer(nil)
+			err := WriteSummary(w, summary, test.format)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+			if got := w.String(); got != test.expected {
+				t.Errorf("unexpected value: got\n%v\nexpected\n%v\n", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotStatus = errors.New("is not a status")
+	ErrNotTicket = errors.New("is not a ticket")
+	ErrParse     = errors.New("cannot be parsed to a ticket")
+)
+
+type Status string
+
+const (
+	Ready         Status = "Готово"
+	InProgress    Status = "В работе"
+	WillNotBeDone Status = "Не будет сделано"
+)
+
+func IsStatus(s string) bool {
+	_, ok := Statuses.Lookup(s)
+	return ok
+}
+
+func IsTicket(s string) bool {
+	_, err := ParseTicketID(s)
+	return err == nil
+}
+
+type Ticket struct {
+	Ticket string
+	User   string
+	Status string
+	Date   time.Time
+}
+
+func NewTicket(ticket string, user string, status string, date time.Time) (*Ticket, error) {
+	if _, err := ParseTicketID(ticket); err != nil {
+		return nil, err
+	}
+	canonical, ok := Statuses.Lookup(status)
+	if !ok {
+		return nil, fmt.Errorf("%v %w", status, ErrNotStatus)
+	}
+	return &Ticket{Ticket: ticket, User: user, Status: string(canonical), Date: date}, nil
+}
+
+func ParseTicket(s string, sep string, layout string) (*Ticket, error) {
+	return UnderscoreDecoder{Sep: sep, Dates: DateParser{Layouts: []string{layout}}}.Decode(s)
+}
+
+// IsTarget сравнивает статусы через Statuses, поэтому псевдоним совпадает с каноническим статусом.
+func (t *Ticket) IsTarget(user *string, status *string) bool {
+	if user != nil && *user != t.User {
+		return false
+	}
+	if status != nil && !sameStatus(*status, t.Status) {
+		return false
+	}
+
+	return true
+}
+
+func sameStatus(a, b string) bool {
+	if a == b {
+		return true
+	}
+	canonicalA, okA := Statuses.Lookup(a)
+	canonicalB, okB := Statuses.Lookup(b)
+	return okA && okB && canonicalA == canonicalB
+}
+
+type Line struct {
+	Text string
+	Err  error
+}
+
+// scanLines читает r единственной горутиной по правилам options. Каждая отправка ждёт либо получателя, либо отмены ctx,
+// поэтому после отмены горутина живёт не дольше текущего вызова Read.
+func scanLines(ctx context.Context, r io.Reader, options *LineOptions) <-chan Line {
+	lines := make(chan Line)
+
+	go func() {
+		defer close(lines)
+
+		send := func(line Line) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case lines <- line:
+				return true
+			}
+		}
+
+		scanner, state := options.scanner(r)
+		for ctx.Err() == nil && scanner.Scan() {
+			if state.skipped {
+				state.skipped = false
+				if !send(Line{Err: &LineTooLongError{Limit: options.maxSize()}}) {
+					return
+				}
+				continue
+			}
+			if !send(Line{Text: scanner.Text(), Err: scanner.Err()}) || scanner.Err() != nil {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			send(Line{Err: err})
+		}
+	}()
+
+	return lines
+}
+
+// ReadLines возвращает канал строк из r. Канал закрывается сразу после отмены ctx,
+// даже если r в этот момент заблокирован в Read.
+func ReadLines(ctx context.Context, r io.Reader) <-chan Line {
+	return ReadLinesWith(ctx, r, nil)
+}
+
+// ReadLinesWith работает как ReadLines, но делит поток и ограничивает длину строк по options.
+// Пропущенная длинная строка приходит как Line с *LineTooLongError, после неё чтение продолжается.
+func ReadLinesWith(ctx context.Context, r io.Reader, options *LineOptions) <-chan Line {
+	channel := make(chan Line)
+
+	go func() {
+		defer close(channel)
+
+		lines := scanLines(ctx, r, options)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case line, ok := <-lines:
+				if !ok {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case channel <- line:
+				}
+			}
+		}
+	}()
+
+	return channel
+}
+
+// ReadLinesCloser работает как ReadLines, но при отмене ctx закрывает rc,
+// чтобы прервать заблокированный Read.
+func ReadLinesCloser(ctx context.Context, rc io.ReadCloser) <-chan Line {
+	stop := context.AfterFunc(ctx, func() { rc.Close() })
+
+	channel := make(chan Line)
+	go func() {
+		defer close(channel)
+		defer stop()
+
+		for line := range ReadLines(ctx, rc) {
+			select {
+			case <-ctx.Done():
+				return
+			case channel <- line:
+			}
+		}
+	}()
+
+	return channel
+}
+
+// ScanTickets читает строки из r, разбирает их decoder'ом и передаёт каждый тикет в fn.
+// Строки, которые не удалось разобрать, пропускаются и попадают в diagnostics, если он задан.
+// Дедлайн задаёт вызывающий через ctx.
+func ScanTickets(ctx context.Context, r io.Reader, decoder TicketDecoder, diagnostics *Diagnostics, fn func(t *Ticket) error) error {
+	return ScanTicketsWith(ctx, r, nil, decoder, diagnostics, fn)
+}
+
+// ScanTicketsWith работает как ScanTickets, но читает строки по правилам options.
+// Пропущенные длинные строки попадают в diagnostics так же, как неразобранные.
+func ScanTicketsWith(ctx context.Context, r io.Reader, options *LineOptions, decoder TicketDecoder, diagnostics *Diagnostics, fn func(t *Ticket) error) error {
+	return ScanTicketLines(ctx, r, options, decoder, diagnostics, func(line TicketLine) error {
+		return fn(line.Ticket)
+	})
+}
+
+// TicketLine — разобранный тикет вместе с номером и исходным текстом строки.
+type TicketLine struct {
+	Number int
+	Text   string
+	Ticket *Ticket
+}
+
+// ScanTicketLines работает как ScanTicketsWith, но передаёт в fn и строку, из которой получен тикет.
+func ScanTicketLines(ctx context.Context, r io.Reader, options *LineOptions, decoder TicketDecoder, diagnostics *Diagnostics, fn func(line TicketLine) error) error {
+	if decoder == nil {
+		decoder = DefaultDecoder
+	}
+
+	number := 0
+	lines := ReadLinesWith(ctx, r, options)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				return ctx.Err()
+			}
+
+			var tooLong *LineTooLongError
+			if errors.As(line.Err, &tooLong) {
+				number++
+				if err := diagnostics.reject(number, "", line.Err); err != nil {
+					return err
+				}
+				continue
+			}
+			if line.Err != nil {
+				return line.Err
+			}
+
+			number++
+			text := strings.TrimSpace(line.Text)
+			if text == "" {
+				continue
+			}
+
+			theTicket, err := decoder.Decode(text)
+			if err != nil {
+				if err := diagnostics.reject(number, line.Text, err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := fn(TicketLine{Number: number, Text: line.Text, Ticket: theTicket}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetTasksText — строковый вариант GetTasks: разбирает text построчно
+// и возвращает тикеты user и status (nil — любой). Строки, не являющиеся
+// тикетами, пропускаются.
+func GetTasksText(text string, user *string, status *string) []Ticket {
+	filter := TargetFilter(user, status)
+
+	tickets := make([]Ticket, 0)
+	_ = ScanTickets(context.Background(), strings.NewReader(text), DefaultDecoder, nil, func(t *Ticket) error {
+		if filter.Match(t) {
+			tickets = append(tickets, *t)
+		}
+		return nil
+	})
+	return tickets
+}
+
+// NoTimeout отключает дедлайн GetTasksWith: остаётся только ctx вызывающего.
+const NoTimeout time.Duration = -1
+
+func GetTasks(ctx context.Context, r io.Reader, w io.Writer, decoder TicketDecoder, filter Filter, timeout time.Duration) error {
+	return GetTasksWith(ctx, r, w, nil, decoder, filter, nil, timeout)
+}
+
+// GetTasksWith работает как GetTasks, но читает строки по options, а неразобранные строки
+// передаёт в diagnostics. В w пишется только полный результат: при ошибке чтения w не трогается.
+func GetTasksWith(ctx context.Context, r io.Reader, w io.Writer, options *LineOptions, decoder TicketDecoder, filter Filter, diagnostics *Diagnostics, timeout time.Duration) error {
+	cancel := context.CancelFunc(func() {})
+	if timeout != NoTimeout {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	tikets := make([]Ticket, 0)
+	err := ScanTicketsWith(ctx, r, options, decoder, diagnostics, func(t *Ticket) error {
+		if filter.Match(t) {
+			tikets = append(tikets, *t)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(tikets)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	return nil
+}
@@ -1,35 +1,24 @@
-package main
+package ticket
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"reflect"
+	"runtime"
 	"slices"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/galiullindo/go-2-step-by-step/step1/testutils"
 )
 
 var diff = 5 * time.Millisecond
 
-var ErrCustom = errors.New("custom error")
-
-type CustomReader struct {
-	f func(p []byte) (n int, err error)
-}
-
-func NewCustomReader(f func(p []byte) (n int, err error)) *CustomReader {
-	return &CustomReader{f: f}
-}
-
-func (r *CustomReader) Read(b []byte) (n int, err error) {
-	n, err = r.f(b)
-	return n, err
-}
-
 func TestIsStatus(t *testing.T) {
 	var tests = []struct {
 		name     string
@@ -41,7 +30,9 @@ func TestIsStatus(t *testing.T) {
 		{name: "Case status WillNotBeDone", s: "Не будет сделано", expected: true},
 		{name: "Case random string", s: "абвгдеё", expected: false},
 		{name: "Case empty string", s: "", expected: false},
-		{name: "Case sensitivity check", s: "ГОТОВО", expected: false},
+		{name: "Case sensitivity check", s: "ГОТОВО", expected: true},
+		{name: "Case whitespace check", s: "  в   работе ", expected: true},
+		{name: "Case english alias", s: "Won’t do", expected: true},
 	}
 
 	for _, test := range tests {
@@ -61,7 +52,11 @@ func TestIsTicket(t *testing.T) {
 		s        string
 		expected bool
 	}{
-		{name: "Case valid ticket", s: "TICKET-...", expected: true},
+		{name: "Case valid ticket", s: "TICKET-12345", expected: true},
+		{name: "Case project key", s: "TICKET-PAY-123", expected: true},
+		{name: "Case dots instead of number", s: "TICKET-...", expected: false},
+		{name: "Case prefix only", s: "TICKET-", expected: false},
+		{name: "Case words", s: "TICKET-foo bar", expected: false},
 		{name: "Case invalid ticket", s: "TICKET...", expected: false},
 		{name: "Case random string", s: "абвгдеё", expected: false},
 		{name: "Case empty string", s: "", expected: false},
@@ -170,7 +165,7 @@ func TestParseTicket(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := ParseTicket(test.s, "_", "2006-01-02")
+			got, err := ParseTicket(test.s, "_", DateLayout)
 
 			if !errors.Is(err, test.expectedErr) {
 				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
@@ -192,6 +187,7 @@ func TestIsTarget(t *testing.T) {
 		emptyStatus  string = ""
 		targetStatus string = "Готово"
 		anoterStatus string = "Победа!"
+		aliasStatus  string = " done "
 	)
 
 	var tests = []struct {
@@ -250,6 +246,20 @@ func TestIsTarget(t *testing.T) {
 			ticket:   &Ticket{Ticket: "TICKET-12345", User: "user", Status: "Готово", Date: time.Date(2026, 1, 3, 00, 0, 0, 0, time.UTC)},
 			expected: false,
 		},
+		{
+			name:     "Case status alias",
+			user:     &targetUser,
+			status:   &aliasStatus,
+			ticket:   &Ticket{Ticket: "TICKET-12345", User: "user", Status: "Готово", Date: time.Date(2026, 1, 3, 00, 0, 0, 0, time.UTC)},
+			expected: true,
+		},
+		{
+			name:     "Case ticket status alias",
+			user:     &targetUser,
+			status:   &targetStatus,
+			ticket:   &Ticket{Ticket: "TICKET-12345", User: "user", Status: "Сделано", Date: time.Date(2026, 1, 3, 00, 0, 0, 0, time.UTC)},
+			expected: true,
+		},
 		{
 			name:     "Case another status",
 			user:     &targetUser,
@@ -292,7 +302,7 @@ func TestReadLines(t *testing.T) {
 		{
 			name:    "Case delaytion",
 			timeout: 10 * time.Millisecond,
-			reader: NewCustomReader(func(p []byte) (n int, err error) {
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
 				time.Sleep(20 * time.Millisecond)
 				n = copy(p, []byte("abcdefg"))
 				return n, nil
@@ -301,19 +311,29 @@ func TestReadLines(t *testing.T) {
 		{
 			name:    "Case endless reading",
 			timeout: 10 * time.Millisecond,
-			reader: NewCustomReader(func(p []byte) (n int, err error) {
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				time.Sleep(time.Millisecond)
 				n = copy(p, []byte("abcdefg"))
 				return n, nil
 			}),
 		},
+		{
+			name:    "Case endless line",
+			timeout: 10 * time.Millisecond,
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
+				n = copy(p, bytes.Repeat([]byte("abcdefg"), len(p)/7+1))
+				return n, nil
+			}),
+			expected: []Line{{"", bufio.ErrTooLong}},
+		},
 		{
 			name:    "Case read error",
 			timeout: 10 * time.Millisecond,
-			reader: NewCustomReader(func(p []byte) (n int, err error) {
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
 				n = copy(p, []byte("abcdefg"))
-				return n, ErrCustom
+				return n, testutils.FakeReadError
 			}),
-			expected: []Line{{"abcdefg", ErrCustom}},
+			expected: []Line{{"abcdefg", testutils.FakeReadError}},
 		},
 	}
 
@@ -345,6 +365,102 @@ func TestReadLines(t *testing.T) {
 	}
 }
 
+// readerStacks возвращает стеки горутин ReadLines по их заголовкам.
+func readerStacks() map[string]string {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+
+	stacks := make(map[string]string)
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		if strings.Contains(stack, "ticket.scanLines") || strings.Contains(stack, "ticket.ReadLines") {
+			header, _, _ := strings.Cut(stack, " [")
+			stacks[header] = stack
+		}
+	}
+	return stacks
+}
+
+// leakedReaders возвращает стеки горутин, оставшихся от ReadLines,
+// кроме запущенных до before. Горутинам даётся время завершиться после отмены.
+func leakedReaders(before map[string]string) []string {
+	deadline := time.Now().Add(time.Second)
+	for {
+		leaked := make([]string, 0)
+		for header, stack := range readerStacks() {
+			if _, ok := before[header]; !ok {
+				leaked = append(leaked, stack)
+			}
+		}
+
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadLinesLeak(t *testing.T) {
+	endless := func() io.Reader {
+		return testutils.ReaderFunc(func(p []byte) (n int, err error) {
+			return copy(p, []byte("abc\n")), nil
+		})
+	}
+
+	var tests = []struct {
+		name string
+		read func(ctx context.Context) <-chan Line
+		take int
+	}{
+		{
+			name: "Case consumer stops reading",
+			read: func(ctx context.Context) <-chan Line { return ReadLines(ctx, endless()) },
+			take: 1,
+		},
+		{
+			name: "Case consumer reads nothing",
+			read: func(ctx context.Context) <-chan Line { return ReadLines(ctx, endless()) },
+		},
+		{
+			name: "Case closer consumer stops reading",
+			read: func(ctx context.Context) <-chan Line { return ReadLinesCloser(ctx, io.NopCloser(endless())) },
+			take: 1,
+		},
+		{
+			name: "Case blocked reader closed",
+			read: func(ctx context.Context) <-chan Line {
+				r, _ := io.Pipe()
+				return ReadLinesCloser(ctx, r)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := readerStacks()
+			ctx, cancel := context.WithCancel(context.Background())
+
+			lines := test.read(ctx)
+			for range test.take {
+				<-lines
+			}
+			cancel()
+
+			if leaked := leakedReaders(before); len(leaked) != 0 {
+				t.Errorf("unexpected goroutines: %v\n", leaked)
+			}
+
+			select {
+			case _, ok := <-lines:
+				if ok {
+					t.Errorf("unexpected open channel after cancel\n")
+				}
+			case <-time.After(diff):
+				t.Errorf("unexpected open channel after cancel\n")
+			}
+		})
+	}
+}
+
 func TestGetTasks(t *testing.T) {
 	var (
 		user   string = "user"
@@ -352,6 +468,7 @@ func TestGetTasks(t *testing.T) {
 
 		ready      string = "Готово"
 		inProgress string = "В работе"
+		doing      string = "Doing"
 	)
 	var lines = []string{
 		"TICKET-12345_user_Готово_2026-01-02",
@@ -480,6 +597,21 @@ func TestGetTasks(t *testing.T) {
 				return string(s)
 			}(),
 		},
+		{
+			name:    "Case user's tickets by status alias",
+			reader:  strings.NewReader(strings.Join(lines, "\n")),
+			writer:  bytes.NewBuffer(nil),
+			user:    &user,
+			status:  &doing,
+			timeout: 10 * time.Millisecond,
+			expected: func() string {
+				s, _ := json.Marshal([]Ticket{
+					{Ticket: "TICKET-12346", User: "user", Status: "В работе", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+					{Ticket: "TICKET-12347", User: "user", Status: "В работе", Date: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+				})
+				return string(s)
+			}(),
+		},
 		{
 			name:    "Case annother's tickets ready",
 			reader:  strings.NewReader(strings.Join(lines, "\n")),
@@ -497,18 +629,18 @@ func TestGetTasks(t *testing.T) {
 		},
 		{
 			name: "Case read error",
-			reader: NewCustomReader(func(p []byte) (n int, err error) {
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
 				n = copy(p, []byte("TICKET-12345_user_Готово_2026-01-02\n"))
-				err = ErrCustom
+				err = testutils.FakeReadError
 				return n, err
 			}),
 			writer:      bytes.NewBuffer(nil),
 			timeout:     10 * time.Millisecond,
-			expectedErr: ErrCustom,
+			expectedErr: testutils.FakeReadError,
 		},
 		{
 			name: "Case delation",
-			reader: NewCustomReader(func(p []byte) (n int, err error) {
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
 				time.Sleep(20 * time.Millisecond)
 				n = copy(p, []byte("TICKET-12345_user_Готово_2026-01-02\n"))
 				return n, err
@@ -519,7 +651,7 @@ func TestGetTasks(t *testing.T) {
 		},
 		{
 			name: "Case endless reading",
-			reader: NewCustomReader(func(p []byte) (n int, err error) {
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
 				n = copy(p, []byte("TICKET-12345_user_Готово_2026-01-02\n"))
 				return n, err
 			}),
@@ -529,7 +661,7 @@ func TestGetTasks(t *testing.T) {
 		},
 		{
 			name: "Case timeout 0",
-			reader: NewCustomReader(func(p []byte) (n int, err error) {
+			reader: testutils.ReaderFunc(func(p []byte) (n int, err error) {
 				n = copy(p, []byte("TICKET-12345_user_Готово_2026-01-02\n"))
 				return n, err
 			}),
@@ -547,7 +679,7 @@ func TestGetTasks(t *testing.T) {
 			defer cancel()
 
 			start := time.Now()
-			err := GetTasks(ctx, test.reader, test.writer, test.user, test.status, test.timeout)
+			err := GetTasks(ctx, test.reader, test.writer, DefaultDecoder, TargetFilter(test.user, test.status), test.timeout)
 			duration := time.Since(start)
 
 			timeout := test.timeout + diff
@@ -572,3 +704,93 @@ func TestGetTasks(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTasksWith(t *testing.T) {
+	text := "TICKET-1_alice_Готово_2026-01-01\r\nsome-text\r\nTICKET-2_bob_Готово_2026-01-02\r\n"
+
+	w := bytes.NewBuffer(nil)
+	diagnostics := &Diagnostics{}
+	err := GetTasksWith(context.Background(), strings.NewReader(text), w, &LineOptions{Split: ScanCRLF}, DefaultDecoder, TargetFilter(nil, nil), diagnostics, NoTimeout)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	expected := `[{"Ticket":"TICKET-1","User":"alice","Status":"Готово","Date":"2026-01-01T00:00:00Z"},` +
+		`{"Ticket":"TICKET-2","User":"bob","Status":"Готово","Date":"2026-01-02T00:00:00Z"}]`
+	if got := w.String(); got != expected {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, expected)
+	}
+	if len(diagnostics.Rejections) != 1 || diagnostics.Rejections[0].Line != 2 {
+		t.Errorf("unexpected rejections: got %v, expected line %v\n", diagnostics.Rejections, 2)
+	}
+}
+
+func TestGetTasksWithError(t *testing.T) {
+	blocked, unblock := context.WithCancel(context.Background())
+	defer unblock()
+
+	w := bytes.NewBuffer(nil)
+	err := GetTasksWith(context.Background(), testutils.NewBlockingReader(blocked), w, nil, DefaultDecoder, TargetFilter(nil, nil), nil, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, context.DeadlineExceeded)
+	}
+	if w.Len() != 0 {
+		t.Errorf("unexpected value: got %v, expected nothing written\n", w.String())
+	}
+}
+
+func TestGetTasksText(t *testing.T) {
+	var (
+		user  string = "user"
+		ready string = "Готово"
+	)
+	var text = strings.Join([]string{
+		"TICKET-12345_user_Готово_2026-01-02",
+		"  TICKET-12346_user_В работе_2026-01-03  ",
+		"TICKET-12347_another_Готово_2026-01-04",
+		"invalid-ticket_user_Готово_2026-01-03",
+		"TICKET-12348_user_invalid-status_2026-01-03",
+		"TICKET-12349_user_Готово_invalid-data",
+		"some-text",
+	}, "\n")
+
+	var tests = []struct {
+		name     string
+		user     *string
+		status   *string
+		expected []Ticket
+	}{
+		{
+			name: "Case all tickets",
+			expected: []Ticket{
+				{Ticket: "TICKET-12345", User: "user", Status: "Готово", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Ticket: "TICKET-12346", User: "user", Status: "В работе", Date: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+				{Ticket: "TICKET-12347", User: "another", Status: "Готово", Date: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:   "Case user's ready tickets",
+			user:   &user,
+			status: &ready,
+			expected: []Ticket{
+				{Ticket: "TICKET-12345", User: "user", Status: "Готово", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:     "Case no tickets",
+			user:     new(string),
+			expected: []Ticket{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := GetTasksText(text, test.user, test.status)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package ticket
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TicketIDError объясняет, почему строка не является идентификатором тикета.
+type TicketIDError struct {
+	Text string
+	Msg  string
+}
+
+func (e *TicketIDError) Error() string {
+	return fmt.Sprintf("%q %v: %v", e.Text, ErrNotTicket, e.Msg)
+}
+
+func (e *TicketIDError) Unwrap() error {
+	return ErrNotTicket
+}
+
+// TicketGrammar описывает идентификаторы вида PREFIX-[PROJECT-]SUFFIX, например TICKET-123 или TICKET-PAY-123.
+// Ключ проекта — заглавные латинские буквы и цифры, начинается с буквы. Суффикс — цифры,
+// а с Alphanumeric ещё и латинские буквы; в любом случае он заканчивается номером.
+type TicketGrammar struct {
+	Prefix       string
+	Projects     bool
+	Alphanumeric bool
+}
+
+// IDGrammar — грамматика, по которой IsTicket и NewTicket проверяют идентификаторы.
+var IDGrammar = TicketGrammar{Prefix: "TICKET", Projects: true}
+
+// TicketID — разобранный идентификатор. Number — числовое окончание суффикса,
+// по нему идентификаторы сравниваются как числа: TICKET-9 < TICKET-10.
+type TicketID struct {
+	Prefix  string
+	Project string
+	Suffix  string
+	Number  uint64
+}
+
+func (id TicketID) String() string {
+	parts := []string{id.Prefix}
+	if id.Project != "" {
+		parts = append(parts, id.Project)
+	}
+	return strings.Join(append(parts, id.Suffix), "-")
+}
+
+// CompareTicketIDs упорядочивает идентификаторы по префиксу, проекту, номеру и затем по суффиксу целиком.
+func CompareTicketIDs(a TicketID, b TicketID) int {
+	return cmp.Or(
+		strings.Compare(a.Prefix, b.Prefix),
+		strings.Compare(a.Project, b.Project),
+		cmp.Compare(a.Number, b.Number),
+		strings.Compare(a.Suffix, b.Suffix),
+	)
+}
+
+func isUpper(r rune) bool { return r >= 'A' && r <= 'Z' }
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+func (g TicketGrammar) Parse(s string) (TicketID, error) {
+	fail := func(format string, args ...any) (TicketID, error) {
+		return TicketID{}, &TicketIDError{Text: s, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	rest, ok := strings.CutPrefix(s, g.Prefix+"-")
+	if !ok {
+		return fail("must start with %q", g.Prefix+"-")
+	}
+
+	id := TicketID{Prefix: g.Prefix}
+	switch parts := strings.Split(rest, "-"); {
+	case len(parts) == 1:
+		id.Suffix = parts[0]
+	case len(parts) == 2 && g.Projects:
+		id.Project, id.Suffix = parts[0], parts[1]
+		if id.Project == "" {
+			return fail("empty project key")
+		}
+		if !isUpper(rune(id.Project[0])) || strings.ContainsFunc(id.Project, func(r rune) bool { return !isUpper(r) && !isDigit(r) }) {
+			return fail("project key %q must be upper-case latin letters and digits", id.Project)
+		}
+	default:
+		return fail("unexpected %q", "-"+strings.Join(parts[1:], "-"))
+	}
+
+	if id.Suffix == "" {
+		return fail("missing number")
+	}
+	for _, r := range id.Suffix {
+		if isDigit(r) || (g.Alphanumeric && (isUpper(r) || (r >= 'a' && r <= 'z'))) {
+			continue
+		}
+		return fail("unexpected %q in %q", r, id.Suffix)
+	}
+
+	digits := len(id.Suffix) - len(strings.TrimRightFunc(id.Suffix, isDigit))
+	if digits == 0 {
+		return fail("%q does not end with a number", id.Suffix)
+	}
+	number, err := strconv.ParseUint(id.Suffix[len(id.Suffix)-digits:], 10, 64)
+	if err != nil {
+		return fail("number %v is too large", id.Suffix[len(id.Suffix)-digits:])
+	}
+	id.Number = number
+
+	return id, nil
+}
+
+// ParseTicketID разбирает идентификатор по IDGrammar.
+func ParseTicketID(s string) (TicketID, error) {
+	return IDGrammar.Parse(s)
+}
+
+// ID разбирает идентификатор тикета по IDGrammar.
+func (t Ticket) ID() (TicketID, error) {
+	return ParseTicketID(t.Ticket)
+}
+
+// compareTicketStrings сравнивает идентификаторы по CompareTicketIDs, а строки, которые
+// не разбираются, — как строки и после разобранных.
+func compareTicketStrings(a string, b string) int {
+	idA, errA := ParseTicketID(a)
+	idB, errB := ParseTicketID(b)
+	switch {
+	case errA == nil && errB == nil:
+		return CompareTicketIDs(idA, idB)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,97 @@
+package ticket
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTicketGrammar(t *testing.T) {
+	alphanumeric := TicketGrammar{Prefix: "TICKET", Projects: true, Alphanumeric: true}
+	plain := TicketGrammar{Prefix: "BUG"}
+
+	var tests = []struct {
+		name        string
+		grammar     TicketGrammar
+		s           string
+		expected    TicketID
+		expectedMsg string
+	}{
+		{name: "Case number", grammar: IDGrammar, s: "TICKET-123", expected: TicketID{Prefix: "TICKET", Suffix: "123", Number: 123}},
+		{name: "Case project", grammar: IDGrammar, s: "TICKET-PAY-123", expected: TicketID{Prefix: "TICKET", Project: "PAY", Suffix: "123", Number: 123}},
+		{name: "Case leading zeros", grammar: IDGrammar, s: "TICKET-007", expected: TicketID{Prefix: "TICKET", Suffix: "007", Number: 7}},
+		{name: "Case alphanumeric", grammar: alphanumeric, s: "TICKET-PAY-rc12", expected: TicketID{Prefix: "TICKET", Project: "PAY", Suffix: "rc12", Number: 12}},
+		{name: "Case other prefix", grammar: plain, s: "BUG-42", expected: TicketID{Prefix: "BUG", Suffix: "42", Number: 42}},
+		{name: "Case prefix only", grammar: IDGrammar, s: "TICKET-", expectedMsg: "missing number"},
+		{name: "Case no dash", grammar: IDGrammar, s: "TICKET123", expectedMsg: `must start with "TICKET-"`},
+		{name: "Case lower case prefix", grammar: IDGrammar, s: "ticket-1", expectedMsg: `must start with "TICKET-"`},
+		{name: "Case words", grammar: IDGrammar, s: "TICKET-foo bar", expectedMsg: `unexpected 'f' in "foo bar"`},
+		{name: "Case letters without alphanumeric", grammar: IDGrammar, s: "TICKET-12a", expectedMsg: `unexpected 'a' in "12a"`},
+		{name: "Case no number", grammar: alphanumeric, s: "TICKET-abc", expectedMsg: `"abc" does not end with a number`},
+		{name: "Case lower case project", grammar: IDGrammar, s: "TICKET-pay-1", expectedMsg: `project key "pay" must be upper-case latin letters and digits`},
+		{name: "Case empty project", grammar: IDGrammar, s: "TICKET--1", expectedMsg: "empty project key"},
+		{name: "Case project not allowed", grammar: plain, s: "BUG-PAY-1", expectedMsg: `unexpected "-1"`},
+		{name: "Case too many parts", grammar: IDGrammar, s: "TICKET-PAY-1-2", expectedMsg: `unexpected "-1-2"`},
+		{name: "Case too large", grammar: IDGrammar, s: "TICKET-99999999999999999999", expectedMsg: "number 99999999999999999999 is too large"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.grammar.Parse(test.s)
+
+			if test.expectedMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v\n", err)
+				}
+				if got != test.expected || got.String() != test.s {
+					t.Errorf("unexpected value: got %#v (%v), expected %#v\n", got, got, test.expected)
+				}
+				return
+			}
+
+			var idErr *TicketIDError
+			if !errors.Is(err, ErrNotTicket) || !errors.As(err, &idErr) || idErr.Msg != test.expectedMsg {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedMsg)
+			}
+		})
+	}
+}
+
+func TestCompareTicketIDs(t *testing.T) {
+	ids := []string{"TICKET-10", "TICKET-PAY-2", "TICKET-9", "TICKET-PAY-10", "TICKET-010", "some-text", "TICKET-1"}
+	expected := []string{"TICKET-1", "TICKET-9", "TICKET-010", "TICKET-10", "TICKET-PAY-2", "TICKET-PAY-10", "some-text"}
+
+	slices.SortFunc(ids, compareTicketStrings)
+	if !slices.Equal(ids, expected) {
+		t.Errorf("unexpected order: got %v, expected %v\n", ids, expected)
+	}
+
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	tickets := []Ticket{{Ticket: "TICKET-10", Date: day}, {Ticket: "TICKET-9", Date: day}}
+	slices.SortFunc(tickets, CompareTickets)
+	if tickets[0].Ticket != "TICKET-9" {
+		t.Errorf("unexpected order: got %v\n", tickets)
+	}
+
+	filter, err := CompileFilter("ticket < TICKET-10 and ticket >= TICKET-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	got := make([]string, 0)
+	for _, id := range []string{"TICKET-1", "TICKET-2", "TICKET-9", "TICKET-10", "TICKET-100"} {
+		if filter.Match(&Ticket{Ticket: id}) {
+			got = append(got, id)
+		}
+	}
+	if expected := []string{"TICKET-2", "TICKET-9"}; !slices.Equal(got, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, expected)
+	}
+
+	if _, err := NewTicket("TICKET-", "user", "Готово", day); err == nil || !strings.Contains(err.Error(), "missing number") {
+		t.Errorf("unexpected error: %v\n", err)
+	}
+}
@@ -0,0 +1,121 @@
+package ticket
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrNotTransition = errors.New("is not an allowed transition")
+	ErrNotSameTicket = errors.New("is not the same ticket")
+)
+
+type TransitionError struct {
+	Ticket string
+	From   Status
+	To     Status
+	Date   time.Time
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%v %v -> %v at %v %v", e.Ticket, e.From, e.To, e.Date.Format(DateLayout), ErrNotTransition)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrNotTransition
+}
+
+// Start обозначает состояние тикета до первой строки в логе.
+const Start Status = ""
+
+var DefaultTransitions = map[Status][]Status{
+	Start:         {InProgress, Ready, WillNotBeDone},
+	InProgress:    {Ready, WillNotBeDone},
+	Ready:         {InProgress},
+	WillNotBeDone: {InProgress},
+}
+
+// Workflow проверяет переходы между статусами по таблице from -> []to.
+// Переход в тот же статус разрешён всегда. Если в таблице нет Start, первым может быть любой статус.
+type Workflow struct {
+	transitions map[Status][]Status
+}
+
+func NewWorkflow(transitions map[Status][]Status) *Workflow {
+	table := make(map[Status][]Status, len(transitions))
+	for from, to := range transitions {
+		table[from] = slices.Clone(to)
+	}
+	return &Workflow{transitions: table}
+}
+
+func (w *Workflow) Allowed(from Status, to Status) bool {
+	if from == to {
+		return true
+	}
+	if from == Start {
+		if _, found := w.transitions[Start]; !found {
+			return true
+		}
+	}
+	return slices.Contains(w.transitions[from], to)
+}
+
+// Fold проходит строки одного тикета в порядке дат и возвращает итоговое состояние.
+// Недопустимые переходы не останавливают проход и возвращаются как *TransitionError.
+func (w *Workflow) Fold(lines []Ticket) (*Ticket, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	ordered := slices.Clone(lines)
+	slices.SortStableFunc(ordered, func(a Ticket, b Ticket) int { return a.Date.Compare(b.Date) })
+
+	errs := make([]error, 0)
+	state := Start
+	for _, line := range ordered {
+		if line.Ticket != ordered[0].Ticket {
+			return nil, fmt.Errorf("%v %w %v", line.Ticket, ErrNotSameTicket, ordered[0].Ticket)
+		}
+		next, ok := Statuses.Lookup(line.Status)
+		if !ok {
+			return nil, fmt.Errorf("%v %w", line.Status, ErrNotStatus)
+		}
+
+		if !w.Allowed(state, next) {
+			errs = append(errs, &TransitionError{Ticket: line.Ticket, From: state, To: next, Date: line.Date})
+		}
+		state = next
+	}
+
+	last := ordered[len(ordered)-1]
+	return &last, errors.Join(errs...)
+}
+
+// Replay группирует тикеты по идентификатору и сворачивает каждую группу через Fold.
+func (w *Workflow) Replay(tickets []Ticket) (map[string]*Ticket, error) {
+	groups := make(map[string][]Ticket)
+	order := make([]string, 0)
+	for _, ticket := range tickets {
+		if _, found := groups[ticket.Ticket]; !found {
+			order = append(order, ticket.Ticket)
+		}
+		groups[ticket.Ticket] = append(groups[ticket.Ticket], ticket)
+	}
+
+	states := make(map[string]*Ticket, len(groups))
+	errs := make([]error, 0)
+	for _, id := range order {
+		state, err := w.Fold(groups[id])
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if state != nil {
+			states[id] = state
+		}
+	}
+
+	return states, errors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package ticket
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorkflowAllowed(t *testing.T) {
+	var tests = []struct {
+		name        string
+		transitions map[Status][]Status
+		from        Status
+		to          Status
+		expected    bool
+	}{
+		{name: "Case default start", transitions: DefaultTransitions, from: Start, to: InProgress, expected: true},
+		{name: "Case default finish", transitions: DefaultTransitions, from: InProgress, to: Ready, expected: true},
+		{name: "Case default forbidden", transitions: DefaultTransitions, from: Ready, to: WillNotBeDone, expected: false},
+		{name: "Case same status", transitions: DefaultTransitions, from: Ready, to: Ready, expected: true},
+		{name: "Case any start", transitions: map[Status][]Status{InProgress: {Ready}}, from: Start, to: Ready, expected: true},
+		{name: "Case restricted start", transitions: map[Status][]Status{Start: {InProgress}}, from: Start, to: Ready, expected: false},
+		{name: "Case empty table", transitions: map[Status][]Status{}, from: InProgress, to: Ready, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			workflow := NewWorkflow(test.transitions)
+			if got := workflow.Allowed(test.from, test.to); got != test.expected {
+				t.Errorf("unexpected value for %v -> %v: got %v, expected %v\n", test.from, test.to, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestWorkflowFold(t *testing.T) {
+	var (
+		day1 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		day2 = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+		day3 = time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+	)
+
+	var tests = []struct {
+		name        string
+		lines       []Ticket
+		expected    *Ticket
+		expectedErr error
+		transitions []TransitionError
+	}{
+		{
+			name: "Case empty",
+		},
+		{
+			name: "Case legal in date order",
+			lines: []Ticket{
+				{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day2},
+				{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1},
+			},
+			expected: &Ticket{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day2},
+		},
+		{
+			name: "Case illegal transition",
+			lines: []Ticket{
+				{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1},
+				{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day2},
+				{Ticket: "TICKET-1", User: "user", Status: "Не будет сделано", Date: day3},
+			},
+			expected:    &Ticket{Ticket: "TICKET-1", User: "user", Status: "Не будет сделано", Date: day3},
+			expectedErr: ErrNotTransition,
+			transitions: []TransitionError{{Ticket: "TICKET-1", From: Ready, To: WillNotBeDone, Date: day3}},
+		},
+		{
+			name: "Case another ticket",
+			lines: []Ticket{
+				{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1},
+				{Ticket: "TICKET-2", User: "user", Status: "Готово", Date: day2},
+			},
+			expectedErr: ErrNotSameTicket,
+		},
+		{
+			name: "Case invalid status",
+			lines: []Ticket{
+				{Ticket: "TICKET-1", User: "user", Status: "Победа!", Date: day1},
+			},
+			expectedErr: ErrNotStatus,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewWorkflow(DefaultTransitions).Fold(test.lines)
+
+			if !errors.Is(err, test.expectedErr) {
+				t.Errorf("unexpected error: got %v, expected %v\n", err, test.expectedErr)
+			}
+
+			var transitionErr *TransitionError
+			if len(test.transitions) > 0 {
+				if !errors.As(err, &transitionErr) {
+					t.Fatalf("unexpected error type: %T\n", err)
+				}
+				if *transitionErr != test.transitions[0] {
+					t.Errorf("unexpected transition: got %v, expected %v\n", *transitionErr, test.transitions[0])
+				}
+			}
+
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("unexpected value: got %v, expected %v\n", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestWorkflowReplay(t *testing.T) {
+	var (
+		day1 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		day2 = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	)
+
+	tickets := []Ticket{
+		{Ticket: "TICKET-1", User: "user", Status: "В работе", Date: day1},
+		{Ticket: "TICKET-2", User: "another", Status: "Готово", Date: day1},
+		{Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day2},
+		{Ticket: "TICKET-2", User: "another", Status: "Не будет сделано", Date: day2},
+	}
+
+	got, err := NewWorkflow(DefaultTransitions).Replay(tickets)
+	if !errors.Is(err, ErrNotTransition) {
+		t.Errorf("unexpected error: got %v, expected %v\n", err, ErrNotTransition)
+	}
+
+	expected := map[string]*Ticket{
+		"TICKET-1": {Ticket: "TICKET-1", User: "user", Status: "Готово", Date: day2},
+		"TICKET-2": {Ticket: "TICKET-2", User: "another", Status: "Не будет сделано", Date: day2},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected value: got %v, expected %v\n", got, expected)
+	}
+}